@@ -8,7 +8,21 @@ terraform {
 }
 
 provider "apisix" {
-  env = "local"
+  env             = "local"
+  request_timeout = 30
+  client_secret_source = {
+    env = "OIDC_CLIENT_SECRET_{client_id}"
+  }
+}
+
+provider "apisix" {
+  alias         = "uat"
+  env           = "uat"
+  profiles_file = "profiles.yaml"
+  ca_cert       = file("uat-ca.pem")
+  client_secret_source = {
+    reference = "$secret://vault/oidc/{client_id}"
+  }
 }
 
 resource "apisix_route" "ssf-java-sdk-springboot3-demo-dynLoggingLevel" {
@@ -34,3 +48,72 @@ resource "apisix_route" "ssf-java-sdk-springboot3-demo-dynLoggingLevel" {
   }
   status = 1
 }
+
+resource "apisix_service" "ssf-java-sdk-springboot3-demo" {
+  id          = "ssf-java-sdk-springboot3-demo"
+  name        = "ssf-java-sdk-springboot3-demo"
+  desc        = "Shared upstream and plugins of ssf-java-sdk-springboot3-demo routes"
+  upstream_id = "1"
+  plugins = {
+    openid_connect = {
+      client_id = "client-id"
+      discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
+      required_scopes = ["admin"]
+    }
+  }
+}
+
+resource "apisix_consumer" "ssf-java-sdk-springboot3-demo-client" {
+  username = "ssf_java_sdk_springboot3_demo_client"
+  desc     = "Client of ssf-java-sdk-springboot3-demo"
+  plugins = {
+    key_auth = {
+      key = var.demo_client_key
+    }
+  }
+}
+
+variable "demo_client_key" {
+  type      = string
+  sensitive = true
+}
+
+resource "apisix_ssl" "example-com" {
+  id   = "example-com"
+  cert = file("certs/example.com.crt")
+  key  = file("certs/example.com.key")
+  snis = ["example.com", "www.example.com"]
+}
+
+resource "apisix_stream_route" "mysql" {
+  id          = "mysql"
+  server_port = 9100
+  remote_addr = "10.0.0.0/8"
+  upstream = {
+    type  = "roundrobin"
+    nodes = [{
+      host   = "172.18.21.10"
+      port   = 3306
+      weight = 1
+    }]
+  }
+}
+
+resource "apisix_secret" "vault" {
+  id = "vault"
+  vault = {
+    uri    = "https://vault.example.com:8200"
+    prefix = "kv/apisix"
+    token  = var.vault_token
+  }
+}
+
+variable "vault_token" {
+  type      = string
+  sensitive = true
+}
+
+resource "apisix_proto" "greeter" {
+  id      = "greeter"
+  content = file("protos/helloworld.proto")
+}
@@ -0,0 +1,380 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// KeyAuthPlugin, JwtAuthPlugin, BasicAuthPlugin and HmacAuthPlugin authenticate requests against
+// the credentials of consumers, see apisix_consumer and apisix_consumer_credential.
+type KeyAuthPlugin struct {
+	Header          types.String `tfsdk:"header"`
+	Query           types.String `tfsdk:"query"`
+	HideCredentials types.Bool   `tfsdk:"hide_credentials"`
+}
+
+type JwtAuthPlugin struct {
+	Header          types.String `tfsdk:"header"`
+	Query           types.String `tfsdk:"query"`
+	Cookie          types.String `tfsdk:"cookie"`
+	KeyClaimName    types.String `tfsdk:"key_claim_name"`
+	HideCredentials types.Bool   `tfsdk:"hide_credentials"`
+}
+
+type BasicAuthPlugin struct {
+	HideCredentials types.Bool `tfsdk:"hide_credentials"`
+}
+
+type HmacAuthPlugin struct {
+	AllowedAlgorithms   []string    `tfsdk:"allowed_algorithms"`
+	ClockSkew           types.Int64 `tfsdk:"clock_skew"`
+	SignedHeaders       []string    `tfsdk:"signed_headers"`
+	ValidateRequestBody types.Bool  `tfsdk:"validate_request_body"`
+	HideCredentials     types.Bool  `tfsdk:"hide_credentials"`
+}
+
+// ForwardAuthPlugin authenticates requests against an external authorization service.
+type ForwardAuthPlugin struct {
+	Uri              types.String `tfsdk:"uri"`
+	RequestMethod    types.String `tfsdk:"request_method"`
+	RequestHeaders   []string     `tfsdk:"request_headers"`
+	UpstreamHeaders  []string     `tfsdk:"upstream_headers"`
+	ClientHeaders    []string     `tfsdk:"client_headers"`
+	Timeout          types.Int64  `tfsdk:"timeout"`
+	SslVerify        types.Bool   `tfsdk:"ssl_verify"`
+	Keepalive        types.Bool   `tfsdk:"keepalive"`
+	AllowDegradation types.Bool   `tfsdk:"allow_degradation"`
+	StatusOnError    types.Int64  `tfsdk:"status_on_error"`
+}
+
+func hideCredentialsAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Remove the credentials from the request passed to the upstream, default false",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+}
+
+func keyAuthPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"header": schema.StringAttribute{
+				MarkdownDescription: "Header the key is read from, default apikey",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("apikey"),
+			},
+			"query": schema.StringAttribute{
+				MarkdownDescription: "Query argument the key is read from when the header is absent, default apikey",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("apikey"),
+			},
+			"hide_credentials": hideCredentialsAttribute(),
+		},
+		MarkdownDescription: "key-auth plugin, authenticates consumers by key",
+		Optional:            true,
+	}
+}
+
+func jwtAuthPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"header": schema.StringAttribute{
+				MarkdownDescription: "Header the token is read from, default authorization",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("authorization"),
+			},
+			"query": schema.StringAttribute{
+				MarkdownDescription: "Query argument the token is read from when the header is absent, default jwt",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("jwt"),
+			},
+			"cookie": schema.StringAttribute{
+				MarkdownDescription: "Cookie the token is read from when the header and query argument are absent, default jwt",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("jwt"),
+			},
+			"key_claim_name": schema.StringAttribute{
+				MarkdownDescription: "Claim holding the key of the consumer, default key",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("key"),
+			},
+			"hide_credentials": hideCredentialsAttribute(),
+		},
+		MarkdownDescription: "jwt-auth plugin, authenticates consumers by JSON web token",
+		Optional:            true,
+	}
+}
+
+func basicAuthPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"hide_credentials": hideCredentialsAttribute(),
+		},
+		MarkdownDescription: "basic-auth plugin, authenticates consumers by username and password",
+		Optional:            true,
+	}
+}
+
+func hmacAuthPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"allowed_algorithms": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Signature algorithms accepted, default all of hmac-sha1, hmac-sha256 and hmac-sha512",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf("hmac-sha1", "hmac-sha256", "hmac-sha512")),
+				},
+			},
+			"clock_skew": schema.Int64Attribute{
+				MarkdownDescription: "Max seconds between the Date header and the time of apisix, 0 disables the check. Default 300",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(300),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"signed_headers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Headers that must be signed",
+				Optional:            true,
+			},
+			"validate_request_body": schema.BoolAttribute{
+				MarkdownDescription: "Validate the Digest header against the request body, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"hide_credentials": hideCredentialsAttribute(),
+		},
+		MarkdownDescription: "hmac-auth plugin, authenticates consumers by HMAC signature",
+		Optional:            true,
+	}
+}
+
+func forwardAuthPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"uri": schema.StringAttribute{
+				MarkdownDescription: "URI of the authorization service",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpUrlRegex, "must be a http(s) URL"),
+				},
+			},
+			"request_method": schema.StringAttribute{
+				MarkdownDescription: "Method of the authorization request, POST forwards the request body. Default GET",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("GET"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("GET", "POST"),
+				},
+			},
+			"request_headers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Client request headers forwarded to the authorization service",
+				Optional:            true,
+			},
+			"upstream_headers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Authorization response headers passed to the upstream when authorized",
+				Optional:            true,
+			},
+			"client_headers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Authorization response headers returned to the client when rejected",
+				Optional:            true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Timeout in milliseconds of the authorization request, default 3000",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3000),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 60000),
+				},
+			},
+			"ssl_verify": schema.BoolAttribute{
+				MarkdownDescription: "Verify the certificate of the authorization service, default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"keepalive": schema.BoolAttribute{
+				MarkdownDescription: "Keep connections to the authorization service alive, default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"allow_degradation": schema.BoolAttribute{
+				MarkdownDescription: "Let requests through when the authorization service fails, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"status_on_error": schema.Int64Attribute{
+				MarkdownDescription: "Status code returned when the authorization service fails, default 403",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(403),
+				Validators: []validator.Int64{
+					int64validator.Between(200, 599),
+				},
+			},
+		},
+		MarkdownDescription: "forward-auth plugin, authenticates requests against an external authorization service",
+		Optional:            true,
+	}
+}
+
+func buildKeyAuthPlugin(plugin *model.KeyAuthPlugin) *KeyAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &KeyAuthPlugin{
+		Header:          types.StringValue(plugin.Header),
+		Query:           types.StringValue(plugin.Query),
+		HideCredentials: types.BoolValue(plugin.HideCredentials),
+	}
+}
+
+func buildInfraKeyAuthPlugin(plugin *KeyAuthPlugin) *model.KeyAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.KeyAuthPlugin{
+		Header:          plugin.Header.ValueString(),
+		Query:           plugin.Query.ValueString(),
+		HideCredentials: plugin.HideCredentials.ValueBool(),
+	}
+}
+
+func buildJwtAuthPlugin(plugin *model.JwtAuthPlugin) *JwtAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &JwtAuthPlugin{
+		Header:          types.StringValue(plugin.Header),
+		Query:           types.StringValue(plugin.Query),
+		Cookie:          types.StringValue(plugin.Cookie),
+		KeyClaimName:    types.StringValue(plugin.KeyClaimName),
+		HideCredentials: types.BoolValue(plugin.HideCredentials),
+	}
+}
+
+func buildInfraJwtAuthPlugin(plugin *JwtAuthPlugin) *model.JwtAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.JwtAuthPlugin{
+		Header:          plugin.Header.ValueString(),
+		Query:           plugin.Query.ValueString(),
+		Cookie:          plugin.Cookie.ValueString(),
+		KeyClaimName:    plugin.KeyClaimName.ValueString(),
+		HideCredentials: plugin.HideCredentials.ValueBool(),
+	}
+}
+
+func buildBasicAuthPlugin(plugin *model.BasicAuthPlugin) *BasicAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &BasicAuthPlugin{
+		HideCredentials: types.BoolValue(plugin.HideCredentials),
+	}
+}
+
+func buildInfraBasicAuthPlugin(plugin *BasicAuthPlugin) *model.BasicAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.BasicAuthPlugin{
+		HideCredentials: plugin.HideCredentials.ValueBool(),
+	}
+}
+
+// buildHmacAuthPlugin converts the plugin returned by apisix, the algorithms apisix fills in are
+// only taken when they were configured.
+func buildHmacAuthPlugin(plugin *model.HmacAuthPlugin, prior *HmacAuthPlugin) *HmacAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &HmacAuthPlugin{
+		AllowedAlgorithms:   plugin.AllowedAlgorithms,
+		ClockSkew:           types.Int64Value(int64(plugin.ClockSkew)),
+		SignedHeaders:       plugin.SignedHeaders,
+		ValidateRequestBody: types.BoolValue(plugin.ValidateRequestBody),
+		HideCredentials:     types.BoolValue(plugin.HideCredentials),
+	}
+	if prior != nil && prior.AllowedAlgorithms == nil {
+		built.AllowedAlgorithms = nil
+	}
+	return built
+}
+
+func buildInfraHmacAuthPlugin(plugin *HmacAuthPlugin) *model.HmacAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.HmacAuthPlugin{
+		AllowedAlgorithms:   plugin.AllowedAlgorithms,
+		ClockSkew:           int(plugin.ClockSkew.ValueInt64()),
+		SignedHeaders:       plugin.SignedHeaders,
+		ValidateRequestBody: plugin.ValidateRequestBody.ValueBool(),
+		HideCredentials:     plugin.HideCredentials.ValueBool(),
+	}
+}
+
+func buildForwardAuthPlugin(plugin *model.ForwardAuthPlugin) *ForwardAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &ForwardAuthPlugin{
+		Uri:              types.StringValue(plugin.Uri),
+		RequestMethod:    types.StringValue(plugin.RequestMethod),
+		RequestHeaders:   plugin.RequestHeaders,
+		UpstreamHeaders:  plugin.UpstreamHeaders,
+		ClientHeaders:    plugin.ClientHeaders,
+		Timeout:          types.Int64Value(int64(plugin.Timeout)),
+		SslVerify:        types.BoolValue(plugin.SslVerify),
+		Keepalive:        types.BoolValue(plugin.Keepalive),
+		AllowDegradation: types.BoolValue(plugin.AllowDegradation),
+		StatusOnError:    types.Int64Value(int64(plugin.StatusOnError)),
+	}
+}
+
+func buildInfraForwardAuthPlugin(plugin *ForwardAuthPlugin) *model.ForwardAuthPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.ForwardAuthPlugin{
+		Uri:              plugin.Uri.ValueString(),
+		RequestMethod:    plugin.RequestMethod.ValueString(),
+		RequestHeaders:   plugin.RequestHeaders,
+		UpstreamHeaders:  plugin.UpstreamHeaders,
+		ClientHeaders:    plugin.ClientHeaders,
+		Timeout:          int(plugin.Timeout.ValueInt64()),
+		SslVerify:        plugin.SslVerify.ValueBool(),
+		Keepalive:        plugin.Keepalive.ValueBool(),
+		AllowDegradation: plugin.AllowDegradation.ValueBool(),
+		StatusOnError:    int(plugin.StatusOnError.ValueInt64()),
+	}
+}
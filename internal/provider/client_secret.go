@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// clientIdPlaceholder is replaced by the client ID of the openid-connect plugin in env, file and reference,
+// so each client can have its own secret.
+const clientIdPlaceholder = "{client_id}"
+
+// clientSecretReferenceRegex matches apisix references resolved by apisix itself when the plugin runs.
+var clientSecretReferenceRegex = regexp.MustCompile(`^\$(secret|env)://.+`)
+
+// ClientSecretSource resolves the openid-connect client secret pushed to apisix.
+type ClientSecretSource interface {
+	ClientSecret(clientId string) (string, error)
+}
+
+// ClientSecretSourceModel describes the client_secret_source provider attribute, exactly one of them is set.
+type ClientSecretSourceModel struct {
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Env          types.String `tfsdk:"env"`
+	File         types.String `tfsdk:"file"`
+	Reference    types.String `tfsdk:"reference"`
+}
+
+// inlineClientSecret is the secret set in the provider configuration.
+type inlineClientSecret string
+
+func (s inlineClientSecret) ClientSecret(clientId string) (string, error) {
+	return string(s), nil
+}
+
+// envClientSecret looks the secret up from the environment variable of that name.
+type envClientSecret string
+
+func (s envClientSecret) ClientSecret(clientId string) (string, error) {
+	name := strings.ReplaceAll(string(s), clientIdPlaceholder, clientId)
+	secret := os.Getenv(name)
+	if secret == "" {
+		return "", fmt.Errorf("env '%s' holding the client secret of client '%s' is not set", name, clientId)
+	}
+	return secret, nil
+}
+
+// fileClientSecret reads the secret from the file of that path, trailing newlines are dropped.
+type fileClientSecret string
+
+func (s fileClientSecret) ClientSecret(clientId string) (string, error) {
+	name := strings.ReplaceAll(string(s), clientIdPlaceholder, clientId)
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("could not read the client secret of client '%s': %w", clientId, err)
+	}
+	secret := strings.TrimRight(string(content), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("file '%s' holding the client secret of client '%s' is empty", name, clientId)
+	}
+	return secret, nil
+}
+
+// referenceClientSecret passes a $secret:// or $env:// reference through to apisix.
+type referenceClientSecret string
+
+func (s referenceClientSecret) ClientSecret(clientId string) (string, error) {
+	return strings.ReplaceAll(string(s), clientIdPlaceholder, clientId), nil
+}
+
+// newClientSecretSource returns the configured source, nil when none is configured.
+func newClientSecretSource(data *ClientSecretSourceModel) ClientSecretSource {
+	switch {
+	case data == nil:
+		return nil
+	case !data.ClientSecret.IsNull():
+		return inlineClientSecret(data.ClientSecret.ValueString())
+	case !data.Env.IsNull():
+		return envClientSecret(data.Env.ValueString())
+	case !data.File.IsNull():
+		return fileClientSecret(data.File.ValueString())
+	case !data.Reference.IsNull():
+		return referenceClientSecret(data.Reference.ValueString())
+	}
+	return nil
+}
+
+func fetchClientSecret(source ClientSecretSource, clientId string) (string, error) {
+	if source == nil {
+		return "", errors.New("openid_connect requires a client secret, set 'client_secret_source' of the provider")
+	}
+	return source.ClientSecret(clientId)
+}
+
+// validateClientSecretSource reports a planned openid_connect plugin when the provider has no client_secret_source,
+// so it fails at plan time instead of part way through apply. The check is skipped until the provider is configured.
+func validateClientSecretSource(ctx context.Context, plan tfsdk.Plan, configured bool, source ClientSecretSource) diag.Diagnostics {
+	var diags diag.Diagnostics
+	// Nothing to check on destroy
+	if !configured || source != nil || plan.Raw.IsNull() {
+		return diags
+	}
+
+	var openIdConnect types.Object
+	pluginPath := path.Root("plugins").AtName("openid_connect")
+	diags.Append(plan.GetAttribute(ctx, pluginPath, &openIdConnect)...)
+	if diags.HasError() || openIdConnect.IsNull() {
+		return diags
+	}
+	diags.AddAttributeError(
+		pluginPath,
+		"Missing client secret source",
+		"openid_connect requires a client secret, set 'client_secret_source' of the provider.",
+	)
+	return diags
+}
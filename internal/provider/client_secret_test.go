@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestClientSecretSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "demo-client")
+	if err := os.WriteFile(file, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OIDC_SECRET_demo-client", "env-secret")
+
+	cases := map[string]struct {
+		source   *ClientSecretSourceModel
+		expected string
+	}{
+		"inline": {
+			source:   &ClientSecretSourceModel{ClientSecret: types.StringValue("inline-secret")},
+			expected: "inline-secret",
+		},
+		"env": {
+			source:   &ClientSecretSourceModel{Env: types.StringValue("OIDC_SECRET_{client_id}")},
+			expected: "env-secret",
+		},
+		"file": {
+			source:   &ClientSecretSourceModel{File: types.StringValue(filepath.Join(filepath.Dir(file), "{client_id}"))},
+			expected: "file-secret",
+		},
+		"reference": {
+			source:   &ClientSecretSourceModel{Reference: types.StringValue("$secret://vault/oidc/{client_id}")},
+			expected: "$secret://vault/oidc/demo-client",
+		},
+	}
+
+	for name, c := range cases {
+		secret, err := fetchClientSecret(newClientSecretSource(c.source), "demo-client")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+		if secret != c.expected {
+			t.Errorf("%s: expected %q, got %q", name, c.expected, secret)
+		}
+	}
+}
+
+func TestClientSecretSourceErrors(t *testing.T) {
+	if _, err := fetchClientSecret(nil, "demo-client"); err == nil {
+		t.Error("expected an error without a source")
+	}
+
+	source := newClientSecretSource(&ClientSecretSourceModel{Env: types.StringValue("OIDC_SECRET_NOT_SET")})
+	if _, err := fetchClientSecret(source, "demo-client"); err == nil {
+		t.Error("expected an error of an unset env")
+	}
+
+	source = newClientSecretSource(&ClientSecretSourceModel{File: types.StringValue(filepath.Join(t.TempDir(), "missing"))})
+	if _, err := fetchClientSecret(source, "demo-client"); err == nil {
+		t.Error("expected an error of a missing file")
+	}
+}
+
+func TestValidateClientSecretSource(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &resource.SchemaResponse{}
+	(&RouteResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	newPlan := func(data *RouteResourceModel) tfsdk.Plan {
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if data != nil {
+			if diags := plan.Set(ctx, data); diags.HasError() {
+				t.Fatal(diags)
+			}
+		}
+		return plan
+	}
+
+	withOpenIdConnect := newPlan(&RouteResourceModel{
+		Plugins: &Plugins{OpenIdConnectPlugin: &OpenIdConnectPlugin{ClientId: "demo-client", Discovery: "https://idp.example.com"}},
+	})
+	source := newClientSecretSource(&ClientSecretSourceModel{ClientSecret: types.StringValue("secret")})
+
+	if diags := validateClientSecretSource(ctx, withOpenIdConnect, true, nil); !diags.HasError() {
+		t.Error("expected an error of openid_connect without a source")
+	}
+	if diags := validateClientSecretSource(ctx, withOpenIdConnect, true, source); diags.HasError() {
+		t.Errorf("unexpected error with a source: %v", diags)
+	}
+	if diags := validateClientSecretSource(ctx, withOpenIdConnect, false, nil); diags.HasError() {
+		t.Errorf("unexpected error before the provider is configured: %v", diags)
+	}
+	if diags := validateClientSecretSource(ctx, newPlan(&RouteResourceModel{}), true, nil); diags.HasError() {
+		t.Errorf("unexpected error without openid_connect: %v", diags)
+	}
+	if diags := validateClientSecretSource(ctx, newPlan(nil), true, nil); diags.HasError() {
+		t.Errorf("unexpected error on destroy: %v", diags)
+	}
+}
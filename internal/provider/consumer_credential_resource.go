@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ConsumerCredentialResource{}
+var _ resource.ResourceWithImportState = &ConsumerCredentialResource{}
+var _ resource.ResourceWithConfigValidators = &ConsumerCredentialResource{}
+
+func NewConsumerCredentialResource() resource.Resource {
+	return &ConsumerCredentialResource{}
+}
+
+// ConsumerCredentialResource defines the resource implementation, credentials require apisix 3.7 or later.
+type ConsumerCredentialResource struct {
+	client *api.ApisixClient
+}
+
+// ConsumerCredentialResourceModel describes the resource data model.
+type ConsumerCredentialResourceModel struct {
+	Username     types.String       `tfsdk:"username"`
+	CredentialId types.String       `tfsdk:"credential_id"`
+	Desc         types.String       `tfsdk:"desc"`
+	Labels       map[string]string  `tfsdk:"labels"`
+	Plugins      *CredentialPlugins `tfsdk:"plugins"`
+}
+
+func (r *ConsumerCredentialResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_consumer_credential"
+}
+
+func (r *ConsumerCredentialResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	credentialPlugins := credentialPluginsSchema("Apisix gateway consumer credential auth plugin, exactly one of them must be set")
+	credentialPlugins.Optional = false
+	credentialPlugins.Required = true
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "consumer credential resource, import it with `username/credential_id`",
+
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer username the credential belongs to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"credential_id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer credential ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer credential desc",
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Apisix gateway consumer credential labels",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"plugins": credentialPlugins,
+		},
+	}
+}
+
+func (r *ConsumerCredentialResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("plugins").AtName("key_auth"),
+			path.MatchRoot("plugins").AtName("basic_auth"),
+			path.MatchRoot("plugins").AtName("jwt_auth"),
+			path.MatchRoot("plugins").AtName("hmac_auth"),
+		),
+	}
+}
+
+func (r *ConsumerCredentialResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func fillConsumerCredentialModel(data *ConsumerCredentialResourceModel, credential *model.ConsumerCredential) {
+	data.Username = types.StringValue(credential.Username)
+	data.CredentialId = types.StringValue(credential.ID)
+	data.Desc = stringValueOrNull(credential.Desc)
+	data.Labels = credential.Labels
+	data.Plugins = buildCredentialPlugins(credential.Plugins, data.Plugins)
+}
+
+func (r *ConsumerCredentialResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ConsumerCredentialResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	credential := &model.ConsumerCredential{
+		ID:       data.CredentialId.ValueString(),
+		Username: data.Username.ValueString(),
+		Desc:     data.Desc.ValueString(),
+		Labels:   data.Labels,
+		Plugins:  buildInfraCredentialPlugins(data.Plugins),
+	}
+
+	createdCredential, err := r.client.CreateConsumerCredential(credential)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating consumer credential",
+			"Could not create consumer credential, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the consumer credential
+	fillConsumerCredentialModel(&data, createdCredential)
+
+	tflog.Trace(ctx, "created a resource "+data.Username.ValueString()+"/"+data.CredentialId.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConsumerCredentialResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ConsumerCredentialResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	credential, err := r.client.GetConsumerCredentialById(data.Username.ValueString(), data.CredentialId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting consumer credential",
+			"Could not get consumer credential, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillConsumerCredentialModel(&data, credential)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConsumerCredentialResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ConsumerCredentialResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	credential := &model.ConsumerCredential{
+		ID:       data.CredentialId.ValueString(),
+		Username: data.Username.ValueString(),
+		Desc:     data.Desc.ValueString(),
+		Labels:   data.Labels,
+		Plugins:  buildInfraCredentialPlugins(data.Plugins),
+	}
+
+	updatedCredential, err := r.client.UpdateConsumerCredential(credential)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating consumer credential",
+			"Could not update consumer credential, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillConsumerCredentialModel(&data, updatedCredential)
+
+	tflog.Trace(ctx, "updated a resource "+data.Username.ValueString()+"/"+data.CredentialId.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConsumerCredentialResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ConsumerCredentialResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteConsumerCredentialById(data.Username.ValueString(), data.CredentialId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting consumer credential",
+			"Could not delete consumer credential, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *ConsumerCredentialResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: username/credential_id. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("username"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("credential_id"), parts[1])...)
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixConsumerCredentialResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_consumer" "jack" {
+    username = "jack"
+ }
+
+resource "apisix_consumer_credential" "jack_key" {
+    username = apisix_consumer.jack.username
+    credential_id = "jack-key"
+    desc = "Key of jack mobile app"
+    plugins = {
+      key_auth = {
+        key = "auth-jack"
+      }
+    }
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "username", "jack"),
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "credential_id", "jack-key"),
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "desc", "Key of jack mobile app"),
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "plugins.key_auth.key", "auth-jack"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "apisix_consumer_credential.jack_key",
+				ImportState:                          true,
+				ImportStateId:                        "jack/jack-key",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "credential_id",
+				// apisix returns the key encrypted when data encryption is enabled
+				ImportStateVerifyIgnore: []string{"plugins.key_auth.key"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_consumer" "jack" {
+    username = "jack"
+ }
+
+resource "apisix_consumer_credential" "jack_key" {
+    username = apisix_consumer.jack.username
+    credential_id = "jack-key"
+    desc = "Hmac key of jack mobile app"
+    plugins = {
+      hmac_auth = {
+        key_id = "jack-key-id"
+        secret_key = "jack-secret-key"
+      }
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "desc", "Hmac key of jack mobile app"),
+					resource.TestCheckNoResourceAttr("apisix_consumer_credential.jack_key", "plugins.key_auth"),
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "plugins.hmac_auth.key_id", "jack-key-id"),
+					resource.TestCheckResourceAttr("apisix_consumer_credential.jack_key", "plugins.hmac_auth.secret_key", "jack-secret-key"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ConsumerGroupResource{}
+var _ resource.ResourceWithImportState = &ConsumerGroupResource{}
+var _ resource.ResourceWithConfigValidators = &ConsumerGroupResource{}
+var _ resource.ResourceWithModifyPlan = &ConsumerGroupResource{}
+
+func NewConsumerGroupResource() resource.Resource {
+	return &ConsumerGroupResource{}
+}
+
+// ConsumerGroupResource defines the resource implementation.
+type ConsumerGroupResource struct {
+	client             *api.ApisixClient
+	clientSecretSource ClientSecretSource
+}
+
+// ConsumerGroupResourceModel describes the resource data model.
+type ConsumerGroupResourceModel struct {
+	ID          types.String                    `tfsdk:"id"`
+	Desc        types.String                    `tfsdk:"desc"`
+	Labels      map[string]string               `tfsdk:"labels"`
+	Plugins     *Plugins                        `tfsdk:"plugins"`
+	PluginsJson map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+}
+
+func (r *ConsumerGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_consumer_group"
+}
+
+func (r *ConsumerGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "consumer group resource, plugins of the group apply to every consumer joining it",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer group ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer group desc",
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Apisix gateway consumer group labels",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"plugins":      pluginsSchema("Apisix gateway consumer group plugins"),
+			"plugins_json": pluginsJsonSchema("Apisix gateway consumer group raw plugins"),
+		},
+	}
+}
+
+func (r *ConsumerGroupResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		pluginsJsonConflictValidator{},
+	}
+}
+
+func (r *ConsumerGroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.clientSecretSource = providerData.ClientSecretSource
+}
+
+// ModifyPlan reports an openid_connect plugin without a client secret source before apply.
+func (r *ConsumerGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(validateClientSecretSource(ctx, req.Plan, r.client != nil, r.clientSecretSource)...)
+}
+
+func buildInfraConsumerGroup(data *ConsumerGroupResourceModel, clientSecretSource ClientSecretSource) (*model.ConsumerGroup, error) {
+	plugins, err := buildInfraPlugins(data.Plugins, data.PluginsJson, clientSecretSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ConsumerGroup{
+		ID:      data.ID.ValueString(),
+		Desc:    data.Desc.ValueString(),
+		Labels:  data.Labels,
+		Plugins: plugins,
+	}, nil
+}
+
+func fillConsumerGroupModel(data *ConsumerGroupResourceModel, consumerGroup *model.ConsumerGroup) {
+	data.ID = types.StringValue(consumerGroup.ID)
+	data.Desc = stringValueOrNull(consumerGroup.Desc)
+	data.Labels = consumerGroup.Labels
+	data.Plugins = buildPlugins(consumerGroup.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(consumerGroup.Plugins, data.PluginsJson)
+}
+
+func (r *ConsumerGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ConsumerGroupResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	consumerGroup, err := buildInfraConsumerGroup(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	createdConsumerGroup, err := r.client.CreateConsumerGroup(consumerGroup)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating consumer group",
+			"Could not create consumer group, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the consumer group
+	fillConsumerGroupModel(&data, createdConsumerGroup)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConsumerGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ConsumerGroupResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	consumerGroup, err := r.client.GetConsumerGroupById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting consumer group",
+			"Could not get consumer group, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillConsumerGroupModel(&data, consumerGroup)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConsumerGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ConsumerGroupResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	consumerGroup, err := buildInfraConsumerGroup(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedConsumerGroup, err := r.client.UpdateConsumerGroup(consumerGroup)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating consumer group",
+			"Could not update consumer group, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillConsumerGroupModel(&data, updatedConsumerGroup)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConsumerGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ConsumerGroupResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteConsumerGroupById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting consumer group",
+			"Could not delete consumer group, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *ConsumerGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
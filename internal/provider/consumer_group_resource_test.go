@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixConsumerGroupResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_consumer_group" "gold" {
+    id = "gold"
+    desc = "Gold tier customers"
+    labels = {
+      tier = "gold"
+    }
+ }
+
+resource "apisix_consumer" "jack" {
+    username = "jack"
+    group_id = apisix_consumer_group.gold.id
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_consumer_group.gold", "id", "gold"),
+					resource.TestCheckResourceAttr("apisix_consumer_group.gold", "desc", "Gold tier customers"),
+					resource.TestCheckResourceAttr("apisix_consumer_group.gold", "labels.tier", "gold"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "group_id", "gold"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_consumer_group.gold",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_consumer_group" "gold" {
+    id = "gold"
+    desc = "Gold tier customers, 2025 contract"
+    labels = {
+      tier = "gold"
+    }
+ }
+
+resource "apisix_consumer" "jack" {
+    username = "jack"
+    group_id = apisix_consumer_group.gold.id
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_consumer_group.gold", "id", "gold"),
+					resource.TestCheckResourceAttr("apisix_consumer_group.gold", "desc", "Gold tier customers, 2025 contract"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,492 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+var consumerUsernameRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ConsumerResource{}
+var _ resource.ResourceWithImportState = &ConsumerResource{}
+var _ resource.ResourceWithConfigValidators = &ConsumerResource{}
+
+func NewConsumerResource() resource.Resource {
+	return &ConsumerResource{}
+}
+
+// ConsumerResource defines the resource implementation.
+type ConsumerResource struct {
+	client *api.ApisixClient
+}
+
+// ConsumerResourceModel describes the resource data model.
+type ConsumerResourceModel struct {
+	Username    types.String                    `tfsdk:"username"`
+	Desc        types.String                    `tfsdk:"desc"`
+	Labels      map[string]string               `tfsdk:"labels"`
+	GroupId     types.String                    `tfsdk:"group_id"`
+	Plugins     *CredentialPlugins              `tfsdk:"plugins"`
+	PluginsJson map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+}
+
+// CredentialPlugins are the auth plugins identifying a consumer, secrets are never read back from apisix
+// as it may return them encrypted, the configured values are kept in state instead.
+type CredentialPlugins struct {
+	KeyAuth   *KeyAuthCredential   `tfsdk:"key_auth"`
+	BasicAuth *BasicAuthCredential `tfsdk:"basic_auth"`
+	JwtAuth   *JwtAuthCredential   `tfsdk:"jwt_auth"`
+	HmacAuth  *HmacAuthCredential  `tfsdk:"hmac_auth"`
+}
+
+type KeyAuthCredential struct {
+	Key types.String `tfsdk:"key"`
+}
+
+type BasicAuthCredential struct {
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+}
+
+type JwtAuthCredential struct {
+	Key                 types.String `tfsdk:"key"`
+	Secret              types.String `tfsdk:"secret"`
+	PublicKey           types.String `tfsdk:"public_key"`
+	Algorithm           types.String `tfsdk:"algorithm"`
+	Exp                 types.Int64  `tfsdk:"exp"`
+	Base64Secret        types.Bool   `tfsdk:"base64_secret"`
+	LifetimeGracePeriod types.Int64  `tfsdk:"lifetime_grace_period"`
+}
+
+type HmacAuthCredential struct {
+	KeyId     types.String `tfsdk:"key_id"`
+	SecretKey types.String `tfsdk:"secret_key"`
+}
+
+func credentialPluginsSchema(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"key_auth": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						MarkdownDescription: "Unique key of the consumer",
+						Required:            true,
+						Sensitive:           true,
+					},
+				},
+				MarkdownDescription: "key-auth credential",
+				Optional:            true,
+			},
+			"basic_auth": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"username": schema.StringAttribute{
+						MarkdownDescription: "Unique username of the consumer",
+						Required:            true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "Password of the consumer",
+						Required:            true,
+						Sensitive:           true,
+					},
+				},
+				MarkdownDescription: "basic-auth credential",
+				Optional:            true,
+			},
+			"jwt_auth": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						MarkdownDescription: "Unique key of the consumer",
+						Required:            true,
+					},
+					"secret": schema.StringAttribute{
+						MarkdownDescription: "Secret used to sign HS256/HS512 tokens",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"public_key": schema.StringAttribute{
+						MarkdownDescription: "PEM encoded public key used to verify RS256/ES256 tokens",
+						Optional:            true,
+					},
+					"algorithm": schema.StringAttribute{
+						MarkdownDescription: "Signing algorithm, one of HS256, HS512, RS256, ES256",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("HS256", "HS512", "RS256", "ES256"),
+						},
+					},
+					"exp": schema.Int64Attribute{
+						MarkdownDescription: "Expiry time of the token in seconds",
+						Optional:            true,
+					},
+					"base64_secret": schema.BoolAttribute{
+						MarkdownDescription: "Set to true if the secret is base64 encoded",
+						Optional:            true,
+					},
+					"lifetime_grace_period": schema.Int64Attribute{
+						MarkdownDescription: "Grace period in seconds to account for clock skew",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "jwt-auth credential",
+				Optional:            true,
+			},
+			"hmac_auth": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"key_id": schema.StringAttribute{
+						MarkdownDescription: "Unique key id of the consumer",
+						Required:            true,
+					},
+					"secret_key": schema.StringAttribute{
+						MarkdownDescription: "Secret key used to sign the request",
+						Required:            true,
+						Sensitive:           true,
+					},
+				},
+				MarkdownDescription: "hmac-auth credential",
+				Optional:            true,
+			},
+		},
+		MarkdownDescription: description,
+		Optional:            true,
+	}
+}
+
+func (r *ConsumerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_consumer"
+}
+
+func (r *ConsumerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "consumer resource",
+
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer username",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(consumerUsernameRegex, "must only contain letters, digits, '_' and '-'"),
+				},
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer desc",
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Apisix gateway consumer labels",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"group_id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway consumer group ID",
+				Optional:            true,
+			},
+			"plugins":      credentialPluginsSchema("Apisix gateway consumer auth plugins"),
+			"plugins_json": pluginsJsonSchema("Apisix gateway consumer raw plugins, like a limit-count of the consumer"),
+		},
+	}
+}
+
+func (r *ConsumerResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		pluginsJsonConflictValidator{},
+	}
+}
+
+func (r *ConsumerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func int64ValueOrNull(value int) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(value))
+}
+
+func buildInfraCredentialPlugins(plugins *CredentialPlugins) *model.CredentialPlugins {
+	if plugins == nil {
+		return nil
+	}
+
+	infraPlugins := &model.CredentialPlugins{}
+	if plugins.KeyAuth != nil {
+		infraPlugins.KeyAuth = &model.KeyAuthCredential{
+			Key: plugins.KeyAuth.Key.ValueString(),
+		}
+	}
+	if plugins.BasicAuth != nil {
+		infraPlugins.BasicAuth = &model.BasicAuthCredential{
+			Username: plugins.BasicAuth.Username.ValueString(),
+			Password: plugins.BasicAuth.Password.ValueString(),
+		}
+	}
+	if plugins.JwtAuth != nil {
+		infraPlugins.JwtAuth = &model.JwtAuthCredential{
+			Key:                 plugins.JwtAuth.Key.ValueString(),
+			Secret:              plugins.JwtAuth.Secret.ValueString(),
+			PublicKey:           plugins.JwtAuth.PublicKey.ValueString(),
+			Algorithm:           plugins.JwtAuth.Algorithm.ValueString(),
+			Exp:                 int(plugins.JwtAuth.Exp.ValueInt64()),
+			Base64Secret:        plugins.JwtAuth.Base64Secret.ValueBool(),
+			LifetimeGracePeriod: int(plugins.JwtAuth.LifetimeGracePeriod.ValueInt64()),
+		}
+	}
+	if plugins.HmacAuth != nil {
+		infraPlugins.HmacAuth = &model.HmacAuthCredential{
+			KeyId:     plugins.HmacAuth.KeyId.ValueString(),
+			SecretKey: plugins.HmacAuth.SecretKey.ValueString(),
+		}
+	}
+	return infraPlugins
+}
+
+// buildCredentialPlugins converts the plugins returned by apisix, keeping the secrets of prior
+// when it is known. Prior is nil on import, then the secrets returned by apisix are used.
+func buildCredentialPlugins(plugins *model.CredentialPlugins, prior *CredentialPlugins) *CredentialPlugins {
+	if plugins == nil {
+		plugins = &model.CredentialPlugins{}
+	}
+
+	// Keep plugins null when none of them is configured, like when only plugins_json is used
+	configured := prior != nil
+
+	built := &CredentialPlugins{}
+	if plugins.KeyAuth != nil {
+		built.KeyAuth = &KeyAuthCredential{
+			Key: types.StringValue(plugins.KeyAuth.Key),
+		}
+		if prior != nil && prior.KeyAuth != nil {
+			built.KeyAuth.Key = prior.KeyAuth.Key
+		}
+	}
+	if plugins.BasicAuth != nil {
+		built.BasicAuth = &BasicAuthCredential{
+			Username: types.StringValue(plugins.BasicAuth.Username),
+			Password: types.StringValue(plugins.BasicAuth.Password),
+		}
+		if prior != nil && prior.BasicAuth != nil {
+			built.BasicAuth.Password = prior.BasicAuth.Password
+		}
+	}
+	if plugins.JwtAuth != nil {
+		built.JwtAuth = &JwtAuthCredential{
+			Key:                 types.StringValue(plugins.JwtAuth.Key),
+			Secret:              stringValueOrNull(plugins.JwtAuth.Secret),
+			PublicKey:           stringValueOrNull(plugins.JwtAuth.PublicKey),
+			Algorithm:           stringValueOrNull(plugins.JwtAuth.Algorithm),
+			Exp:                 int64ValueOrNull(plugins.JwtAuth.Exp),
+			Base64Secret:        types.BoolNull(),
+			LifetimeGracePeriod: int64ValueOrNull(plugins.JwtAuth.LifetimeGracePeriod),
+		}
+		if plugins.JwtAuth.Base64Secret {
+			built.JwtAuth.Base64Secret = types.BoolValue(true)
+		}
+		if prior != nil && prior.JwtAuth != nil {
+			// apisix fills in defaults of algorithm, exp and base64_secret, keep them as configured
+			built.JwtAuth.Secret = prior.JwtAuth.Secret
+			built.JwtAuth.Algorithm = prior.JwtAuth.Algorithm
+			built.JwtAuth.Exp = prior.JwtAuth.Exp
+			built.JwtAuth.Base64Secret = prior.JwtAuth.Base64Secret
+		}
+	}
+	if plugins.HmacAuth != nil {
+		built.HmacAuth = &HmacAuthCredential{
+			KeyId:     types.StringValue(plugins.HmacAuth.KeyId),
+			SecretKey: types.StringValue(plugins.HmacAuth.SecretKey),
+		}
+		if prior != nil && prior.HmacAuth != nil {
+			built.HmacAuth.SecretKey = prior.HmacAuth.SecretKey
+		}
+	}
+	if !configured && *built == (CredentialPlugins{}) {
+		return nil
+	}
+	return built
+}
+
+func fillConsumerModel(data *ConsumerResourceModel, consumer *model.Consumer) {
+	data.Username = types.StringValue(consumer.Username)
+	data.Desc = stringValueOrNull(consumer.Desc)
+	data.Labels = consumer.Labels
+	data.GroupId = stringValueOrNull(consumer.GroupId)
+	data.Plugins = buildCredentialPlugins(consumer.Plugins, data.Plugins)
+	var extra map[string]any
+	if consumer.Plugins != nil {
+		extra = consumer.Plugins.Extra
+	}
+	data.PluginsJson = buildExtraPluginsJson(extra, data.PluginsJson)
+}
+
+func buildInfraConsumer(data *ConsumerResourceModel) (*model.Consumer, error) {
+	extra, err := buildInfraPluginsJson(data.PluginsJson)
+	if err != nil {
+		return nil, err
+	}
+	plugins := buildInfraCredentialPlugins(data.Plugins)
+	if extra != nil {
+		if plugins == nil {
+			plugins = &model.CredentialPlugins{}
+		}
+		plugins.Extra = extra
+	}
+
+	return &model.Consumer{
+		Username: data.Username.ValueString(),
+		Desc:     data.Desc.ValueString(),
+		Labels:   data.Labels,
+		GroupId:  data.GroupId.ValueString(),
+		Plugins:  plugins,
+	}, nil
+}
+
+func (r *ConsumerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ConsumerResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	consumer, err := buildInfraConsumer(&data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	createdConsumer, err := r.client.CreateConsumer(consumer)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating consumer",
+			"Could not create consumer, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the consumer
+	fillConsumerModel(&data, createdConsumer)
+
+	tflog.Trace(ctx, "created a resource "+data.Username.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConsumerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ConsumerResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	consumer, err := r.client.GetConsumerByUsername(data.Username.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting consumer",
+			"Could not get consumer, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillConsumerModel(&data, consumer)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConsumerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ConsumerResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	consumer, err := buildInfraConsumer(&data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedConsumer, err := r.client.UpdateConsumer(consumer)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating consumer",
+			"Could not update consumer, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillConsumerModel(&data, updatedConsumer)
+
+	tflog.Trace(ctx, "updated a resource "+data.Username.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConsumerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ConsumerResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteConsumerByUsername(data.Username.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting consumer",
+			"Could not delete consumer, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *ConsumerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("username"), req, resp)
+}
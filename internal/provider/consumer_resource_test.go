@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixConsumerResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_consumer" "jack" {
+    username = "jack"
+    desc = "Mobile app of jack"
+    labels = {
+      tier = "gold"
+    }
+    plugins = {
+      key_auth = {
+        key = "auth-jack"
+      }
+    }
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "username", "jack"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "desc", "Mobile app of jack"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "labels.tier", "gold"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "plugins.key_auth.key", "auth-jack"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_consumer" "jack" {
+    username = "jack"
+    desc = "Mobile app of jack"
+    plugins = {
+      basic_auth = {
+        username = "jack"
+        password = "jack-password"
+      }
+      jwt_auth = {
+        key = "jack-key"
+        secret = "jack-secret"
+        algorithm = "HS256"
+      }
+    }
+    plugins_json = {
+      "limit-count" = jsonencode({ count = 100, time_window = 60, rejected_code = 429 })
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "username", "jack"),
+					resource.TestCheckNoResourceAttr("apisix_consumer.jack", "plugins.key_auth"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "plugins.basic_auth.username", "jack"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "plugins.basic_auth.password", "jack-password"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "plugins.jwt_auth.key", "jack-key"),
+					resource.TestCheckResourceAttr("apisix_consumer.jack", "plugins.jwt_auth.algorithm", "HS256"),
+					resource.TestCheckResourceAttrSet("apisix_consumer.jack", "plugins_json.limit-count"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestBuildCredentialPlugins(t *testing.T) {
+	// plugins = {} stays an empty object when apisix returns no plugins
+	for name, plugins := range map[string]*model.CredentialPlugins{"none": nil, "empty": {}} {
+		built := buildCredentialPlugins(plugins, &CredentialPlugins{})
+		if built == nil || *built != (CredentialPlugins{}) {
+			t.Errorf("%s: expected empty plugins of configured plugins, got %+v", name, built)
+		}
+		if built := buildCredentialPlugins(plugins, nil); built != nil {
+			t.Errorf("%s: expected null plugins of unset plugins, got %+v", name, built)
+		}
+	}
+
+	// Raw plugins only keep plugins null
+	if built := buildCredentialPlugins(&model.CredentialPlugins{Extra: map[string]any{"limit-count": map[string]any{}}}, nil); built != nil {
+		t.Errorf("expected null plugins with only raw plugins, got %+v", built)
+	}
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GlobalRuleResource{}
+var _ resource.ResourceWithImportState = &GlobalRuleResource{}
+var _ resource.ResourceWithConfigValidators = &GlobalRuleResource{}
+var _ resource.ResourceWithModifyPlan = &GlobalRuleResource{}
+
+func NewGlobalRuleResource() resource.Resource {
+	return &GlobalRuleResource{}
+}
+
+// GlobalRuleResource defines the resource implementation.
+type GlobalRuleResource struct {
+	client             *api.ApisixClient
+	clientSecretSource ClientSecretSource
+}
+
+// GlobalRuleResourceModel describes the resource data model.
+type GlobalRuleResourceModel struct {
+	ID          types.String                    `tfsdk:"id"`
+	Plugins     *Plugins                        `tfsdk:"plugins"`
+	PluginsJson map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+}
+
+func (r *GlobalRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_global_rule"
+}
+
+func (r *GlobalRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "global rule resource, plugins of the rule apply to every request",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway global rule ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"plugins":      pluginsSchema("Apisix gateway global rule plugins, run on every request. At least one of plugins and plugins_json must be set"),
+			"plugins_json": pluginsJsonSchema("Apisix gateway global rule raw plugins"),
+		},
+	}
+}
+
+func (r *GlobalRuleResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("plugins"),
+			path.MatchRoot("plugins_json"),
+		),
+		pluginsJsonConflictValidator{},
+	}
+}
+
+func (r *GlobalRuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.clientSecretSource = providerData.ClientSecretSource
+}
+
+// ModifyPlan reports an openid_connect plugin without a client secret source before apply.
+func (r *GlobalRuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(validateClientSecretSource(ctx, req.Plan, r.client != nil, r.clientSecretSource)...)
+}
+
+func buildInfraGlobalRule(data *GlobalRuleResourceModel, clientSecretSource ClientSecretSource) (*model.GlobalRule, error) {
+	plugins, err := buildInfraPlugins(data.Plugins, data.PluginsJson, clientSecretSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GlobalRule{
+		ID:      data.ID.ValueString(),
+		Plugins: plugins,
+	}, nil
+}
+
+func fillGlobalRuleModel(data *GlobalRuleResourceModel, globalRule *model.GlobalRule) {
+	data.ID = types.StringValue(globalRule.ID)
+	data.Plugins = buildPlugins(globalRule.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(globalRule.Plugins, data.PluginsJson)
+}
+
+func (r *GlobalRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GlobalRuleResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	globalRule, err := buildInfraGlobalRule(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	createdGlobalRule, err := r.client.CreateGlobalRule(globalRule)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating global rule",
+			"Could not create global rule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the global rule
+	fillGlobalRuleModel(&data, createdGlobalRule)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *GlobalRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data GlobalRuleResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	globalRule, err := r.client.GetGlobalRuleById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting global rule",
+			"Could not get global rule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillGlobalRuleModel(&data, globalRule)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GlobalRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data GlobalRuleResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	globalRule, err := buildInfraGlobalRule(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedGlobalRule, err := r.client.UpdateGlobalRule(globalRule)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating global rule",
+			"Could not update global rule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillGlobalRuleModel(&data, updatedGlobalRule)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *GlobalRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data GlobalRuleResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteGlobalRuleById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting global rule",
+			"Could not delete global rule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *GlobalRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"os"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixGlobalRuleResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_global_rule" "auth" {
+    id = "auth"
+    plugins = {
+      openid_connect = {
+        client_id = "client-id"
+        discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
+        required_scopes = ["admin"]
+       }
+    }
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_global_rule.auth", "id", "auth"),
+					resource.TestCheckResourceAttr("apisix_global_rule.auth", "plugins.openid_connect.client_id", "client-id"),
+					resource.TestCheckResourceAttr("apisix_global_rule.auth", "plugins.openid_connect.required_scopes.0", "admin"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_global_rule.auth",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_global_rule" "auth" {
+    id = "auth"
+    plugins = {
+      openid_connect = {
+        client_id = "client-id"
+        discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
+        required_scopes = ["admin", "book"]
+       }
+    }
+    plugins_json = {
+      "real-ip" = jsonencode({
+        source = "http_x_forwarded_for"
+        trusted_addresses = ["10.0.0.0/8"]
+      })
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_global_rule.auth", "plugins.openid_connect.required_scopes.1", "book"),
+					resource.TestCheckResourceAttr("apisix_global_rule.auth", "plugins_json.real-ip", `{"source":"http_x_forwarded_for","trusted_addresses":["10.0.0.0/8"]}`),
+				),
+			},
+			// A plugin set in both plugins and plugins_json is rejected
+			{
+				Config: providerConfig + `
+resource "apisix_global_rule" "auth" {
+    id = "auth"
+    plugins = {
+      limit_req = {
+        rate = 10
+        burst = 5
+        key = "remote_addr"
+      }
+    }
+    plugins_json = {
+      "limit-req" = jsonencode({ rate = 10, burst = 5, key = "remote_addr" })
+    }
+ }
+`,
+				ExpectError: regexp.MustCompile("Conflicting plugin limit-req"),
+			},
+			// A typed plugin set only in plugins_json is rejected as well
+			{
+				Config: providerConfig + `
+resource "apisix_global_rule" "auth" {
+    id = "auth"
+    plugins_json = {
+      "limit-req" = jsonencode({ rate = 10, burst = 5, key = "remote_addr" })
+    }
+ }
+`,
+				ExpectError: regexp.MustCompile("Conflicting plugin limit-req"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestApisixGlobalRuleResourceObservabilityPlugins(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_global_rule" "observability" {
+    id = "observability"
+    plugins = {
+      prometheus = {
+        prefer_name = true
+      }
+      opentelemetry = {
+        sampler = {
+          name = "trace_id_ratio"
+          fraction = 0.1
+        }
+        additional_attributes = ["http_user_agent"]
+      }
+      http_logger = {
+        uri = "http://172.18.21.241:8080/logs"
+        auth_header = "Bearer log-token"
+        log_format = {
+          host = "$host"
+          client_ip = "$remote_addr"
+        }
+        batch_max_size = 100
+      }
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.prometheus.prefer_name", "true"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.opentelemetry.sampler.name", "trace_id_ratio"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.opentelemetry.sampler.fraction", "0.1"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.http_logger.auth_header", "Bearer log-token"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.http_logger.log_format.client_ip", "$remote_addr"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.http_logger.batch_max_size", "100"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.http_logger.inactive_timeout", "5"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.http_logger.name", "http logger"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_global_rule" "observability" {
+    id = "observability"
+    plugins = {
+      zipkin = {
+        endpoint = "http://172.18.21.241:9411/api/v2/spans"
+        sample_ratio = 1
+      }
+      kafka_logger = {
+        brokers = [{
+          host = "172.18.21.241"
+          port = 9092
+        }]
+        kafka_topic = "apisix-access-log"
+        required_acks = -1
+      }
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.zipkin.service_name", "APISIX"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.zipkin.span_version", "2"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.kafka_logger.brokers.0.port", "9092"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.kafka_logger.required_acks", "-1"),
+					resource.TestCheckResourceAttr("apisix_global_rule.observability", "plugins.kafka_logger.producer_type", "async"),
+					resource.TestCheckNoResourceAttr("apisix_global_rule.observability", "plugins.http_logger"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
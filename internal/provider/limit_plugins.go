@@ -0,0 +1,473 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+const (
+	LimitCountPolicyLocal        = "local"
+	LimitCountPolicyRedis        = "redis"
+	LimitCountPolicyRedisCluster = "redis-cluster"
+)
+
+type LimitReqPlugin struct {
+	Rate             types.Float64 `tfsdk:"rate"`
+	Burst            types.Float64 `tfsdk:"burst"`
+	Key              types.String  `tfsdk:"key"`
+	KeyType          types.String  `tfsdk:"key_type"`
+	RejectedCode     types.Int64   `tfsdk:"rejected_code"`
+	RejectedMsg      types.String  `tfsdk:"rejected_msg"`
+	Nodelay          types.Bool    `tfsdk:"nodelay"`
+	AllowDegradation types.Bool    `tfsdk:"allow_degradation"`
+}
+
+type LimitCountPlugin struct {
+	Count                types.Int64        `tfsdk:"count"`
+	TimeWindow           types.Int64        `tfsdk:"time_window"`
+	Key                  types.String       `tfsdk:"key"`
+	KeyType              types.String       `tfsdk:"key_type"`
+	RejectedCode         types.Int64        `tfsdk:"rejected_code"`
+	RejectedMsg          types.String       `tfsdk:"rejected_msg"`
+	Group                types.String       `tfsdk:"group"`
+	AllowDegradation     types.Bool         `tfsdk:"allow_degradation"`
+	ShowLimitQuotaHeader types.Bool         `tfsdk:"show_limit_quota_header"`
+	Policy               types.String       `tfsdk:"policy"`
+	Redis                *LimitCountRedis   `tfsdk:"redis"`
+	RedisCluster         *LimitCountCluster `tfsdk:"redis_cluster"`
+}
+
+type LimitCountRedis struct {
+	Host     types.String `tfsdk:"host"`
+	Port     types.Int64  `tfsdk:"port"`
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+	Database types.Int64  `tfsdk:"database"`
+	Timeout  types.Int64  `tfsdk:"timeout"`
+	Ssl      types.Bool   `tfsdk:"ssl"`
+}
+
+type LimitCountCluster struct {
+	Nodes    []string     `tfsdk:"nodes"`
+	Name     types.String `tfsdk:"name"`
+	Password types.String `tfsdk:"password"`
+	Timeout  types.Int64  `tfsdk:"timeout"`
+	Ssl      types.Bool   `tfsdk:"ssl"`
+}
+
+type LimitConnPlugin struct {
+	Conn                types.Int64   `tfsdk:"conn"`
+	Burst               types.Int64   `tfsdk:"burst"`
+	DefaultConnDelay    types.Float64 `tfsdk:"default_conn_delay"`
+	OnlyUseDefaultDelay types.Bool    `tfsdk:"only_use_default_delay"`
+	Key                 types.String  `tfsdk:"key"`
+	KeyType             types.String  `tfsdk:"key_type"`
+	RejectedCode        types.Int64   `tfsdk:"rejected_code"`
+	RejectedMsg         types.String  `tfsdk:"rejected_msg"`
+	AllowDegradation    types.Bool    `tfsdk:"allow_degradation"`
+}
+
+// limitKeyAttributes are the attributes the three rate limit plugins share.
+func limitKeyAttributes(keyTypes ...string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"key": schema.StringAttribute{
+			MarkdownDescription: "Variable requests are counted by, like remote_addr or $remote_addr $http_x_user for var_combination",
+			Required:            true,
+		},
+		"key_type": schema.StringAttribute{
+			MarkdownDescription: "Type of key, default var",
+			Optional:            true,
+			Computed:            true,
+			Default:             stringdefault.StaticString("var"),
+			Validators: []validator.String{
+				stringvalidator.OneOf(keyTypes...),
+			},
+		},
+		"rejected_code": schema.Int64Attribute{
+			MarkdownDescription: "Status code of rejected requests, default 503",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(503),
+			Validators: []validator.Int64{
+				int64validator.Between(200, 599),
+			},
+		},
+		"rejected_msg": schema.StringAttribute{
+			MarkdownDescription: "Response body of rejected requests",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+		},
+		"allow_degradation": schema.BoolAttribute{
+			MarkdownDescription: "Let requests through when the plugin fails, default false",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+	}
+}
+
+func limitReqPluginSchema() schema.SingleNestedAttribute {
+	attributes := limitKeyAttributes("var", "var_combination")
+	attributes["rate"] = schema.Float64Attribute{
+		MarkdownDescription: "Requests per second allowed",
+		Required:            true,
+		Validators: []validator.Float64{
+			float64validator.AtLeast(0.001),
+		},
+	}
+	attributes["burst"] = schema.Float64Attribute{
+		MarkdownDescription: "Requests per second above rate that are delayed",
+		Required:            true,
+		Validators: []validator.Float64{
+			float64validator.AtLeast(0),
+		},
+	}
+	attributes["nodelay"] = schema.BoolAttribute{
+		MarkdownDescription: "Do not delay requests within burst, default false",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+
+	return schema.SingleNestedAttribute{
+		Attributes:          attributes,
+		MarkdownDescription: "limit-req plugin, leaky bucket rate limiting",
+		Optional:            true,
+	}
+}
+
+func limitCountPluginSchema() schema.SingleNestedAttribute {
+	attributes := limitKeyAttributes("var", "var_combination", "constant")
+	attributes["count"] = schema.Int64Attribute{
+		MarkdownDescription: "Requests allowed in time_window",
+		Required:            true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+	attributes["time_window"] = schema.Int64Attribute{
+		MarkdownDescription: "Window in seconds requests are counted in",
+		Required:            true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+	attributes["group"] = schema.StringAttribute{
+		MarkdownDescription: "Group sharing the counter across routes, the plugin settings of the group must be identical",
+		Optional:            true,
+	}
+	attributes["show_limit_quota_header"] = schema.BoolAttribute{
+		MarkdownDescription: "Return the X-RateLimit-Limit and X-RateLimit-Remaining headers, default true",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(true),
+	}
+	attributes["policy"] = schema.StringAttribute{
+		MarkdownDescription: "Where the counter is kept, local, redis or redis-cluster. Default local",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString(LimitCountPolicyLocal),
+		Validators: []validator.String{
+			stringvalidator.OneOf(LimitCountPolicyLocal, LimitCountPolicyRedis, LimitCountPolicyRedisCluster),
+		},
+	}
+	attributes["redis"] = schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Redis host",
+				Required:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "Redis port, default 6379",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(6379),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Redis ACL username",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Redis password",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"database": schema.Int64Attribute{
+				MarkdownDescription: "Redis database, default 0",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Timeout in milliseconds of redis commands, default 1000",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1000),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"ssl": schema.BoolAttribute{
+				MarkdownDescription: "Connect to redis over TLS, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		MarkdownDescription: "Redis the counter is kept in, required by policy redis",
+		Optional:            true,
+	}
+	attributes["redis_cluster"] = schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"nodes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Redis cluster nodes, like 10.0.0.1:6379",
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Redis cluster name",
+				Required:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Redis cluster password",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Timeout in milliseconds of redis commands, default 1000",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1000),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"ssl": schema.BoolAttribute{
+				MarkdownDescription: "Connect to the redis cluster over TLS, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		MarkdownDescription: "Redis cluster the counter is kept in, required by policy redis-cluster",
+		Optional:            true,
+	}
+
+	return schema.SingleNestedAttribute{
+		Attributes:          attributes,
+		MarkdownDescription: "limit-count plugin, fixed window rate limiting",
+		Optional:            true,
+		Validators: []validator.Object{
+			limitCountPolicyValidator{},
+		},
+	}
+}
+
+func limitConnPluginSchema() schema.SingleNestedAttribute {
+	attributes := limitKeyAttributes("var", "var_combination")
+	attributes["conn"] = schema.Int64Attribute{
+		MarkdownDescription: "Max concurrent requests",
+		Required:            true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+	attributes["burst"] = schema.Int64Attribute{
+		MarkdownDescription: "Excess concurrent requests delayed",
+		Required:            true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(0),
+		},
+	}
+	attributes["default_conn_delay"] = schema.Float64Attribute{
+		MarkdownDescription: "Delay in seconds of excess requests",
+		Required:            true,
+		Validators: []validator.Float64{
+			float64validator.AtLeast(0.001),
+		},
+	}
+	attributes["only_use_default_delay"] = schema.BoolAttribute{
+		MarkdownDescription: "Delay excess requests by default_conn_delay only, default false",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+
+	return schema.SingleNestedAttribute{
+		Attributes:          attributes,
+		MarkdownDescription: "limit-conn plugin, concurrent request limiting",
+		Optional:            true,
+	}
+}
+
+func buildLimitReqPlugin(plugin *model.LimitReqPlugin) *LimitReqPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &LimitReqPlugin{
+		Rate:             types.Float64Value(plugin.Rate),
+		Burst:            types.Float64Value(plugin.Burst),
+		Key:              types.StringValue(plugin.Key),
+		KeyType:          types.StringValue(plugin.KeyType),
+		RejectedCode:     types.Int64Value(int64(plugin.RejectedCode)),
+		RejectedMsg:      stringValueOrNull(plugin.RejectedMsg),
+		Nodelay:          types.BoolValue(plugin.Nodelay),
+		AllowDegradation: types.BoolValue(plugin.AllowDegradation),
+	}
+}
+
+func buildInfraLimitReqPlugin(plugin *LimitReqPlugin) *model.LimitReqPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.LimitReqPlugin{
+		Rate:             plugin.Rate.ValueFloat64(),
+		Burst:            plugin.Burst.ValueFloat64(),
+		Key:              plugin.Key.ValueString(),
+		KeyType:          plugin.KeyType.ValueString(),
+		RejectedCode:     int(plugin.RejectedCode.ValueInt64()),
+		RejectedMsg:      plugin.RejectedMsg.ValueString(),
+		Nodelay:          plugin.Nodelay.ValueBool(),
+		AllowDegradation: plugin.AllowDegradation.ValueBool(),
+	}
+}
+
+// buildLimitCountPlugin converts the plugin returned by apisix, redis passwords of prior are kept
+// as apisix returns them encrypted when data encryption is enabled.
+func buildLimitCountPlugin(plugin *model.LimitCountPlugin, prior *LimitCountPlugin) *LimitCountPlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &LimitCountPlugin{
+		Count:                types.Int64Value(int64(plugin.Count)),
+		TimeWindow:           types.Int64Value(int64(plugin.TimeWindow)),
+		Key:                  types.StringValue(plugin.Key),
+		KeyType:              types.StringValue(plugin.KeyType),
+		RejectedCode:         types.Int64Value(int64(plugin.RejectedCode)),
+		RejectedMsg:          stringValueOrNull(plugin.RejectedMsg),
+		Group:                stringValueOrNull(plugin.Group),
+		AllowDegradation:     types.BoolValue(plugin.AllowDegradation),
+		ShowLimitQuotaHeader: types.BoolValue(plugin.ShowLimitQuotaHeader),
+		Policy:               types.StringValue(plugin.Policy),
+	}
+	if plugin.Policy == "" {
+		built.Policy = types.StringValue(LimitCountPolicyLocal)
+	}
+
+	switch plugin.Policy {
+	case LimitCountPolicyRedis:
+		built.Redis = &LimitCountRedis{
+			Host:     types.StringValue(plugin.RedisHost),
+			Port:     types.Int64Value(int64(plugin.RedisPort)),
+			Username: stringValueOrNull(plugin.RedisUsername),
+			Password: stringValueOrNull(plugin.RedisPassword),
+			Database: types.Int64Value(int64(plugin.RedisDatabase)),
+			Timeout:  types.Int64Value(int64(plugin.RedisTimeout)),
+			Ssl:      types.BoolValue(plugin.RedisSsl),
+		}
+		if prior != nil && prior.Redis != nil {
+			built.Redis.Password = prior.Redis.Password
+		}
+	case LimitCountPolicyRedisCluster:
+		built.RedisCluster = &LimitCountCluster{
+			Nodes:    plugin.RedisClusterNodes,
+			Name:     types.StringValue(plugin.RedisClusterName),
+			Password: stringValueOrNull(plugin.RedisPassword),
+			Timeout:  types.Int64Value(int64(plugin.RedisTimeout)),
+			Ssl:      types.BoolValue(plugin.RedisClusterSsl),
+		}
+		if prior != nil && prior.RedisCluster != nil {
+			built.RedisCluster.Password = prior.RedisCluster.Password
+		}
+	}
+	return built
+}
+
+func buildInfraLimitCountPlugin(plugin *LimitCountPlugin) *model.LimitCountPlugin {
+	if plugin == nil {
+		return nil
+	}
+	infraPlugin := &model.LimitCountPlugin{
+		Count:                int(plugin.Count.ValueInt64()),
+		TimeWindow:           int(plugin.TimeWindow.ValueInt64()),
+		Key:                  plugin.Key.ValueString(),
+		KeyType:              plugin.KeyType.ValueString(),
+		RejectedCode:         int(plugin.RejectedCode.ValueInt64()),
+		RejectedMsg:          plugin.RejectedMsg.ValueString(),
+		Group:                plugin.Group.ValueString(),
+		AllowDegradation:     plugin.AllowDegradation.ValueBool(),
+		ShowLimitQuotaHeader: plugin.ShowLimitQuotaHeader.ValueBool(),
+		Policy:               plugin.Policy.ValueString(),
+	}
+	if plugin.Redis != nil {
+		infraPlugin.RedisHost = plugin.Redis.Host.ValueString()
+		infraPlugin.RedisPort = int(plugin.Redis.Port.ValueInt64())
+		infraPlugin.RedisUsername = plugin.Redis.Username.ValueString()
+		infraPlugin.RedisPassword = plugin.Redis.Password.ValueString()
+		infraPlugin.RedisDatabase = int(plugin.Redis.Database.ValueInt64())
+		infraPlugin.RedisTimeout = int(plugin.Redis.Timeout.ValueInt64())
+		infraPlugin.RedisSsl = plugin.Redis.Ssl.ValueBool()
+	}
+	if plugin.RedisCluster != nil {
+		infraPlugin.RedisClusterNodes = plugin.RedisCluster.Nodes
+		infraPlugin.RedisClusterName = plugin.RedisCluster.Name.ValueString()
+		infraPlugin.RedisPassword = plugin.RedisCluster.Password.ValueString()
+		infraPlugin.RedisTimeout = int(plugin.RedisCluster.Timeout.ValueInt64())
+		infraPlugin.RedisClusterSsl = plugin.RedisCluster.Ssl.ValueBool()
+	}
+	return infraPlugin
+}
+
+func buildLimitConnPlugin(plugin *model.LimitConnPlugin) *LimitConnPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &LimitConnPlugin{
+		Conn:                types.Int64Value(int64(plugin.Conn)),
+		Burst:               types.Int64Value(int64(plugin.Burst)),
+		DefaultConnDelay:    types.Float64Value(plugin.DefaultConnDelay),
+		OnlyUseDefaultDelay: types.BoolValue(plugin.OnlyUseDefaultDelay),
+		Key:                 types.StringValue(plugin.Key),
+		KeyType:             types.StringValue(plugin.KeyType),
+		RejectedCode:        types.Int64Value(int64(plugin.RejectedCode)),
+		RejectedMsg:         stringValueOrNull(plugin.RejectedMsg),
+		AllowDegradation:    types.BoolValue(plugin.AllowDegradation),
+	}
+}
+
+func buildInfraLimitConnPlugin(plugin *LimitConnPlugin) *model.LimitConnPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.LimitConnPlugin{
+		Conn:                int(plugin.Conn.ValueInt64()),
+		Burst:               int(plugin.Burst.ValueInt64()),
+		DefaultConnDelay:    plugin.DefaultConnDelay.ValueFloat64(),
+		OnlyUseDefaultDelay: plugin.OnlyUseDefaultDelay.ValueBool(),
+		Key:                 plugin.Key.ValueString(),
+		KeyType:             plugin.KeyType.ValueString(),
+		RejectedCode:        int(plugin.RejectedCode.ValueInt64()),
+		RejectedMsg:         plugin.RejectedMsg.ValueString(),
+		AllowDegradation:    plugin.AllowDegradation.ValueBool(),
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLimitCountPolicyValidator(t *testing.T) {
+	redisType := types.ObjectType{AttrTypes: map[string]attr.Type{"host": types.StringType}}
+	redis := types.ObjectValueMust(redisType.AttrTypes, map[string]attr.Value{"host": types.StringValue("127.0.0.1")})
+	attributeTypes := map[string]attr.Type{
+		"policy":        types.StringType,
+		"redis":         redisType,
+		"redis_cluster": redisType,
+	}
+
+	cases := map[string]struct {
+		policy       types.String
+		redis        types.Object
+		redisCluster types.Object
+		valid        bool
+	}{
+		"local":                  {types.StringValue(LimitCountPolicyLocal), types.ObjectNull(redisType.AttrTypes), types.ObjectNull(redisType.AttrTypes), true},
+		"default policy":         {types.StringNull(), types.ObjectNull(redisType.AttrTypes), types.ObjectNull(redisType.AttrTypes), true},
+		"redis":                  {types.StringValue(LimitCountPolicyRedis), redis, types.ObjectNull(redisType.AttrTypes), true},
+		"redis cluster":          {types.StringValue(LimitCountPolicyRedisCluster), types.ObjectNull(redisType.AttrTypes), redis, true},
+		"unknown policy":         {types.StringUnknown(), redis, types.ObjectNull(redisType.AttrTypes), true},
+		"redis missing":          {types.StringValue(LimitCountPolicyRedis), types.ObjectNull(redisType.AttrTypes), types.ObjectNull(redisType.AttrTypes), false},
+		"redis cluster missing":  {types.StringValue(LimitCountPolicyRedisCluster), types.ObjectNull(redisType.AttrTypes), types.ObjectNull(redisType.AttrTypes), false},
+		"redis of local":         {types.StringNull(), redis, types.ObjectNull(redisType.AttrTypes), false},
+		"redis of redis cluster": {types.StringValue(LimitCountPolicyRedisCluster), redis, redis, false},
+	}
+
+	for name, c := range cases {
+		value := types.ObjectValueMust(attributeTypes, map[string]attr.Value{
+			"policy":        c.policy,
+			"redis":         c.redis,
+			"redis_cluster": c.redisCluster,
+		})
+		req := validator.ObjectRequest{Path: path.Root("plugins").AtName("limit_count"), ConfigValue: value}
+		resp := &validator.ObjectResponse{}
+		limitCountPolicyValidator{}.ValidateObject(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", name, c.valid, resp.Diagnostics)
+		}
+	}
+}
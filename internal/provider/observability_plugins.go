@@ -0,0 +1,652 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+const (
+	OpenTelemetrySamplerAlwaysOn     = "always_on"
+	OpenTelemetrySamplerAlwaysOff    = "always_off"
+	OpenTelemetrySamplerTraceIdRatio = "trace_id_ratio"
+	OpenTelemetrySamplerParentBase   = "parent_base"
+)
+
+type PrometheusPlugin struct {
+	PreferName types.Bool `tfsdk:"prefer_name"`
+}
+
+type ZipkinPlugin struct {
+	Endpoint    types.String  `tfsdk:"endpoint"`
+	SampleRatio types.Float64 `tfsdk:"sample_ratio"`
+	ServiceName types.String  `tfsdk:"service_name"`
+	ServerAddr  types.String  `tfsdk:"server_addr"`
+	SpanVersion types.Int64   `tfsdk:"span_version"`
+}
+
+// OpenTelemetryPlugin traces requests, the collector it reports to is set in the plugin metadata of opentelemetry.
+type OpenTelemetryPlugin struct {
+	Sampler                          *OpenTelemetrySampler `tfsdk:"sampler"`
+	AdditionalAttributes             []string              `tfsdk:"additional_attributes"`
+	AdditionalHeaderPrefixAttributes []string              `tfsdk:"additional_header_prefix_attributes"`
+}
+
+type OpenTelemetrySampler struct {
+	Name     types.String              `tfsdk:"name"`
+	Fraction types.Float64             `tfsdk:"fraction"`
+	Root     *OpenTelemetryRootSampler `tfsdk:"root"`
+}
+
+// OpenTelemetryRootSampler samples root spans when the sampler is parent_base.
+type OpenTelemetryRootSampler struct {
+	Name     types.String  `tfsdk:"name"`
+	Fraction types.Float64 `tfsdk:"fraction"`
+}
+
+// SkywalkingPlugin traces requests, the oap server it reports to is set in the plugin attributes of apisix.
+type SkywalkingPlugin struct {
+	SampleRatio types.Float64 `tfsdk:"sample_ratio"`
+}
+
+type HttpLoggerPlugin struct {
+	Uri             types.String      `tfsdk:"uri"`
+	AuthHeader      types.String      `tfsdk:"auth_header"`
+	Timeout         types.Int64       `tfsdk:"timeout"`
+	LogFormat       map[string]string `tfsdk:"log_format"`
+	IncludeReqBody  types.Bool        `tfsdk:"include_req_body"`
+	IncludeRespBody types.Bool        `tfsdk:"include_resp_body"`
+	ConcatMethod    types.String      `tfsdk:"concat_method"`
+	SslVerify       types.Bool        `tfsdk:"ssl_verify"`
+	Name            types.String      `tfsdk:"name"`
+	BatchMaxSize    types.Int64       `tfsdk:"batch_max_size"`
+	InactiveTimeout types.Int64       `tfsdk:"inactive_timeout"`
+	BufferDuration  types.Int64       `tfsdk:"buffer_duration"`
+	MaxRetryCount   types.Int64       `tfsdk:"max_retry_count"`
+	RetryDelay      types.Int64       `tfsdk:"retry_delay"`
+}
+
+type KafkaLoggerPlugin struct {
+	Brokers         []KafkaBroker     `tfsdk:"brokers"`
+	KafkaTopic      types.String      `tfsdk:"kafka_topic"`
+	Key             types.String      `tfsdk:"key"`
+	Timeout         types.Int64       `tfsdk:"timeout"`
+	ProducerType    types.String      `tfsdk:"producer_type"`
+	RequiredAcks    types.Int64       `tfsdk:"required_acks"`
+	MetaFormat      types.String      `tfsdk:"meta_format"`
+	LogFormat       map[string]string `tfsdk:"log_format"`
+	IncludeReqBody  types.Bool        `tfsdk:"include_req_body"`
+	IncludeRespBody types.Bool        `tfsdk:"include_resp_body"`
+	Name            types.String      `tfsdk:"name"`
+	BatchMaxSize    types.Int64       `tfsdk:"batch_max_size"`
+	InactiveTimeout types.Int64       `tfsdk:"inactive_timeout"`
+	BufferDuration  types.Int64       `tfsdk:"buffer_duration"`
+	MaxRetryCount   types.Int64       `tfsdk:"max_retry_count"`
+	RetryDelay      types.Int64       `tfsdk:"retry_delay"`
+}
+
+type KafkaBroker struct {
+	Host types.String `tfsdk:"host"`
+	Port types.Int64  `tfsdk:"port"`
+}
+
+func sampleRatioAttribute(description string) schema.Float64Attribute {
+	return schema.Float64Attribute{
+		MarkdownDescription: description,
+		Validators: []validator.Float64{
+			float64validator.Between(0.00001, 1),
+		},
+	}
+}
+
+// loggerAttributes are the attributes the logger plugins share, the batch processor ones
+// control how log entries are buffered and sent.
+func loggerAttributes(defaultName string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"log_format": schema.MapAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Log entry fields, values starting with $ are apisix or nginx variables like $remote_addr. Defaults to the plugin metadata log_format",
+			Optional:            true,
+		},
+		"include_req_body": schema.BoolAttribute{
+			MarkdownDescription: "Log the request body, default false",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"include_resp_body": schema.BoolAttribute{
+			MarkdownDescription: "Log the response body, default false",
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Name of the batch processor, default " + defaultName,
+			Optional:            true,
+			Computed:            true,
+			Default:             stringdefault.StaticString(defaultName),
+		},
+		"batch_max_size": schema.Int64Attribute{
+			MarkdownDescription: "Max entries sent in one batch, default 1000",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(1000),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"inactive_timeout": schema.Int64Attribute{
+			MarkdownDescription: "Seconds without new entries after which the batch is sent, default 5",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(5),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"buffer_duration": schema.Int64Attribute{
+			MarkdownDescription: "Max age in seconds of the oldest entry before the batch is sent, default 60",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(60),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"max_retry_count": schema.Int64Attribute{
+			MarkdownDescription: "Retries before a failed batch is dropped, default 0",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(0),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+		"retry_delay": schema.Int64Attribute{
+			MarkdownDescription: "Seconds between retries, default 1",
+			Optional:            true,
+			Computed:            true,
+			Default:             int64default.StaticInt64(1),
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+	}
+}
+
+func prometheusPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"prefer_name": schema.BoolAttribute{
+				MarkdownDescription: "Label metrics with the route or service name instead of the ID, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		MarkdownDescription: "prometheus plugin, exports metrics of the requests",
+		Optional:            true,
+	}
+}
+
+func zipkinPluginSchema() schema.SingleNestedAttribute {
+	sampleRatio := sampleRatioAttribute("Ratio of requests sampled, from 0.00001 to 1")
+	sampleRatio.Required = true
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "Zipkin span endpoint, like http://127.0.0.1:9411/api/v2/spans",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpUrlRegex, "must be a http(s) URL"),
+				},
+			},
+			"sample_ratio": sampleRatio,
+			"service_name": schema.StringAttribute{
+				MarkdownDescription: "Service name shown in zipkin, default APISIX",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("APISIX"),
+			},
+			"server_addr": schema.StringAttribute{
+				MarkdownDescription: "IPv4 address reported for apisix, default the server address of the request",
+				Optional:            true,
+				Validators: []validator.String{
+					isIPv4Address(),
+				},
+			},
+			"span_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of the span types, default 2",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(2),
+				Validators: []validator.Int64{
+					int64validator.OneOf(1, 2),
+				},
+			},
+		},
+		MarkdownDescription: "zipkin plugin, traces requests to zipkin",
+		Optional:            true,
+	}
+}
+
+func openTelemetryPluginSchema() schema.SingleNestedAttribute {
+	samplerName := schema.StringAttribute{
+		MarkdownDescription: "Sampling strategy, default always_off",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString(OpenTelemetrySamplerAlwaysOff),
+		Validators: []validator.String{
+			stringvalidator.OneOf(OpenTelemetrySamplerAlwaysOn, OpenTelemetrySamplerAlwaysOff, OpenTelemetrySamplerTraceIdRatio, OpenTelemetrySamplerParentBase),
+		},
+	}
+	fraction := schema.Float64Attribute{
+		MarkdownDescription: "Ratio of requests sampled by trace_id_ratio, from 0 to 1. Default 0",
+		Optional:            true,
+		Computed:            true,
+		Default:             float64default.StaticFloat64(0),
+		Validators: []validator.Float64{
+			float64validator.Between(0, 1),
+		},
+	}
+	rootSamplerName := samplerName
+	rootSamplerName.Validators = []validator.String{
+		stringvalidator.OneOf(OpenTelemetrySamplerAlwaysOn, OpenTelemetrySamplerAlwaysOff, OpenTelemetrySamplerTraceIdRatio),
+	}
+
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"sampler": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"name":     samplerName,
+					"fraction": fraction,
+					"root": schema.SingleNestedAttribute{
+						Attributes: map[string]schema.Attribute{
+							"name":     rootSamplerName,
+							"fraction": fraction,
+						},
+						MarkdownDescription: "Sampler of root spans, used when name is parent_base",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Sampler of the traces, samples none by default",
+				Optional:            true,
+			},
+			"additional_attributes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Variables added as span attributes, like http_user_agent",
+				Optional:            true,
+			},
+			"additional_header_prefix_attributes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Prefixes of request headers added as span attributes, like x-my-header-*",
+				Optional:            true,
+			},
+		},
+		MarkdownDescription: "opentelemetry plugin, traces requests to the collector set in the opentelemetry plugin metadata",
+		Optional:            true,
+	}
+}
+
+func skywalkingPluginSchema() schema.SingleNestedAttribute {
+	sampleRatio := sampleRatioAttribute("Ratio of requests sampled, from 0.00001 to 1. Default 1")
+	sampleRatio.Optional = true
+	sampleRatio.Computed = true
+	sampleRatio.Default = float64default.StaticFloat64(1)
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"sample_ratio": sampleRatio,
+		},
+		MarkdownDescription: "skywalking plugin, traces requests to the skywalking oap server set in the apisix config",
+		Optional:            true,
+	}
+}
+
+func httpLoggerPluginSchema() schema.SingleNestedAttribute {
+	attributes := loggerAttributes("http logger")
+	attributes["uri"] = schema.StringAttribute{
+		MarkdownDescription: "URI logs are posted to",
+		Required:            true,
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(httpUrlRegex, "must be a http(s) URL"),
+		},
+	}
+	attributes["auth_header"] = schema.StringAttribute{
+		MarkdownDescription: "Authorization header sent with the logs",
+		Optional:            true,
+		Sensitive:           true,
+	}
+	attributes["timeout"] = schema.Int64Attribute{
+		MarkdownDescription: "Timeout in seconds of posting logs, default 3",
+		Optional:            true,
+		Computed:            true,
+		Default:             int64default.StaticInt64(3),
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+	attributes["concat_method"] = schema.StringAttribute{
+		MarkdownDescription: "How a batch is encoded, json as an array or new_line as lines of JSON. Default json",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString("json"),
+		Validators: []validator.String{
+			stringvalidator.OneOf("json", "new_line"),
+		},
+	}
+	attributes["ssl_verify"] = schema.BoolAttribute{
+		MarkdownDescription: "Verify the certificate of the log server, default false",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+	return schema.SingleNestedAttribute{
+		Attributes:          attributes,
+		MarkdownDescription: "http-logger plugin, posts access logs to a http(s) server",
+		Optional:            true,
+	}
+}
+
+func kafkaLoggerPluginSchema() schema.SingleNestedAttribute {
+	attributes := loggerAttributes("kafka logger")
+	attributes["brokers"] = schema.ListNestedAttribute{
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"host": schema.StringAttribute{
+					MarkdownDescription: "Host of the broker",
+					Required:            true,
+				},
+				"port": schema.Int64Attribute{
+					MarkdownDescription: "Port of the broker",
+					Required:            true,
+					Validators: []validator.Int64{
+						int64validator.Between(1, 65535),
+					},
+				},
+			},
+		},
+		MarkdownDescription: "Kafka brokers",
+		Required:            true,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+		},
+	}
+	attributes["kafka_topic"] = schema.StringAttribute{
+		MarkdownDescription: "Topic logs are pushed to",
+		Required:            true,
+	}
+	attributes["key"] = schema.StringAttribute{
+		MarkdownDescription: "Key of the messages, used for partitioning",
+		Optional:            true,
+	}
+	attributes["timeout"] = schema.Int64Attribute{
+		MarkdownDescription: "Timeout in seconds of pushing logs, default 3",
+		Optional:            true,
+		Computed:            true,
+		Default:             int64default.StaticInt64(3),
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+	attributes["producer_type"] = schema.StringAttribute{
+		MarkdownDescription: "Whether messages are sent sync or async, default async",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString("async"),
+		Validators: []validator.String{
+			stringvalidator.OneOf("async", "sync"),
+		},
+	}
+	attributes["required_acks"] = schema.Int64Attribute{
+		MarkdownDescription: "Acknowledgements the leader waits for, -1 waits for all replicas. Default 1",
+		Optional:            true,
+		Computed:            true,
+		Default:             int64default.StaticInt64(1),
+		Validators: []validator.Int64{
+			int64validator.OneOf(-1, 0, 1),
+		},
+	}
+	attributes["meta_format"] = schema.StringAttribute{
+		MarkdownDescription: "Format of the request info, default as JSON or origin as the raw HTTP request. Default default",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString("default"),
+		Validators: []validator.String{
+			stringvalidator.OneOf("default", "origin"),
+		},
+	}
+	return schema.SingleNestedAttribute{
+		Attributes:          attributes,
+		MarkdownDescription: "kafka-logger plugin, pushes access logs to kafka",
+		Optional:            true,
+	}
+}
+
+func buildPrometheusPlugin(plugin *model.PrometheusPlugin) *PrometheusPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &PrometheusPlugin{
+		PreferName: types.BoolValue(plugin.PreferName),
+	}
+}
+
+func buildInfraPrometheusPlugin(plugin *PrometheusPlugin) *model.PrometheusPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.PrometheusPlugin{
+		PreferName: plugin.PreferName.ValueBool(),
+	}
+}
+
+func buildZipkinPlugin(plugin *model.ZipkinPlugin) *ZipkinPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &ZipkinPlugin{
+		Endpoint:    types.StringValue(plugin.Endpoint),
+		SampleRatio: types.Float64Value(plugin.SampleRatio),
+		ServiceName: types.StringValue(plugin.ServiceName),
+		ServerAddr:  stringValueOrNull(plugin.ServerAddr),
+		SpanVersion: types.Int64Value(int64(plugin.SpanVersion)),
+	}
+}
+
+func buildInfraZipkinPlugin(plugin *ZipkinPlugin) *model.ZipkinPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.ZipkinPlugin{
+		Endpoint:    plugin.Endpoint.ValueString(),
+		SampleRatio: plugin.SampleRatio.ValueFloat64(),
+		ServiceName: plugin.ServiceName.ValueString(),
+		ServerAddr:  plugin.ServerAddr.ValueString(),
+		SpanVersion: int(plugin.SpanVersion.ValueInt64()),
+	}
+}
+
+// buildOpenTelemetryPlugin converts the plugin returned by apisix, the sampler apisix fills in
+// is only taken when it was configured.
+func buildOpenTelemetryPlugin(plugin *model.OpenTelemetryPlugin, prior *OpenTelemetryPlugin) *OpenTelemetryPlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &OpenTelemetryPlugin{
+		AdditionalAttributes:             plugin.AdditionalAttributes,
+		AdditionalHeaderPrefixAttributes: plugin.AdditionalHeaderPrefixAttributes,
+	}
+	if plugin.Sampler != nil && (prior == nil || prior.Sampler != nil) {
+		built.Sampler = &OpenTelemetrySampler{
+			Name:     types.StringValue(plugin.Sampler.Name),
+			Fraction: types.Float64Value(plugin.Sampler.Fraction),
+		}
+		if plugin.Sampler.Root != nil && (prior == nil || prior.Sampler.Root != nil) {
+			built.Sampler.Root = &OpenTelemetryRootSampler{
+				Name:     types.StringValue(plugin.Sampler.Root.Name),
+				Fraction: types.Float64Value(plugin.Sampler.Root.Fraction),
+			}
+		}
+	}
+	return built
+}
+
+func buildInfraOpenTelemetryPlugin(plugin *OpenTelemetryPlugin) *model.OpenTelemetryPlugin {
+	if plugin == nil {
+		return nil
+	}
+	infraPlugin := &model.OpenTelemetryPlugin{
+		AdditionalAttributes:             plugin.AdditionalAttributes,
+		AdditionalHeaderPrefixAttributes: plugin.AdditionalHeaderPrefixAttributes,
+	}
+	if plugin.Sampler != nil {
+		infraPlugin.Sampler = &model.OpenTelemetrySampler{
+			Name:     plugin.Sampler.Name.ValueString(),
+			Fraction: plugin.Sampler.Fraction.ValueFloat64(),
+		}
+		if plugin.Sampler.Root != nil {
+			infraPlugin.Sampler.Root = &model.OpenTelemetrySampler{
+				Name:     plugin.Sampler.Root.Name.ValueString(),
+				Fraction: plugin.Sampler.Root.Fraction.ValueFloat64(),
+			}
+		}
+	}
+	return infraPlugin
+}
+
+func buildSkywalkingPlugin(plugin *model.SkywalkingPlugin) *SkywalkingPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &SkywalkingPlugin{
+		SampleRatio: types.Float64Value(plugin.SampleRatio),
+	}
+}
+
+func buildInfraSkywalkingPlugin(plugin *SkywalkingPlugin) *model.SkywalkingPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.SkywalkingPlugin{
+		SampleRatio: plugin.SampleRatio.ValueFloat64(),
+	}
+}
+
+// buildHttpLoggerPlugin converts the plugin returned by apisix, auth_header of prior is kept
+// as apisix returns it encrypted when data encryption is enabled.
+func buildHttpLoggerPlugin(plugin *model.HttpLoggerPlugin, prior *HttpLoggerPlugin) *HttpLoggerPlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &HttpLoggerPlugin{
+		Uri:             types.StringValue(plugin.Uri),
+		AuthHeader:      stringValueOrNull(plugin.AuthHeader),
+		Timeout:         types.Int64Value(int64(plugin.Timeout)),
+		LogFormat:       plugin.LogFormat,
+		IncludeReqBody:  types.BoolValue(plugin.IncludeReqBody),
+		IncludeRespBody: types.BoolValue(plugin.IncludeRespBody),
+		ConcatMethod:    types.StringValue(plugin.ConcatMethod),
+		SslVerify:       types.BoolValue(plugin.SslVerify),
+		Name:            types.StringValue(plugin.Name),
+		BatchMaxSize:    types.Int64Value(int64(plugin.BatchMaxSize)),
+		InactiveTimeout: types.Int64Value(int64(plugin.InactiveTimeout)),
+		BufferDuration:  types.Int64Value(int64(plugin.BufferDuration)),
+		MaxRetryCount:   types.Int64Value(int64(plugin.MaxRetryCount)),
+		RetryDelay:      types.Int64Value(int64(plugin.RetryDelay)),
+	}
+	if prior != nil && !prior.AuthHeader.IsNull() && plugin.AuthHeader != "" {
+		built.AuthHeader = prior.AuthHeader
+	}
+	return built
+}
+
+func buildInfraHttpLoggerPlugin(plugin *HttpLoggerPlugin) *model.HttpLoggerPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.HttpLoggerPlugin{
+		Uri:             plugin.Uri.ValueString(),
+		AuthHeader:      plugin.AuthHeader.ValueString(),
+		Timeout:         int(plugin.Timeout.ValueInt64()),
+		LogFormat:       plugin.LogFormat,
+		IncludeReqBody:  plugin.IncludeReqBody.ValueBool(),
+		IncludeRespBody: plugin.IncludeRespBody.ValueBool(),
+		ConcatMethod:    plugin.ConcatMethod.ValueString(),
+		SslVerify:       plugin.SslVerify.ValueBool(),
+		Name:            plugin.Name.ValueString(),
+		BatchMaxSize:    int(plugin.BatchMaxSize.ValueInt64()),
+		InactiveTimeout: int(plugin.InactiveTimeout.ValueInt64()),
+		BufferDuration:  int(plugin.BufferDuration.ValueInt64()),
+		MaxRetryCount:   int(plugin.MaxRetryCount.ValueInt64()),
+		RetryDelay:      int(plugin.RetryDelay.ValueInt64()),
+	}
+}
+
+func buildKafkaLoggerPlugin(plugin *model.KafkaLoggerPlugin) *KafkaLoggerPlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &KafkaLoggerPlugin{
+		KafkaTopic:      types.StringValue(plugin.KafkaTopic),
+		Key:             stringValueOrNull(plugin.Key),
+		Timeout:         types.Int64Value(int64(plugin.Timeout)),
+		ProducerType:    types.StringValue(plugin.ProducerType),
+		RequiredAcks:    types.Int64Value(int64(plugin.RequiredAcks)),
+		MetaFormat:      types.StringValue(plugin.MetaFormat),
+		LogFormat:       plugin.LogFormat,
+		IncludeReqBody:  types.BoolValue(plugin.IncludeReqBody),
+		IncludeRespBody: types.BoolValue(plugin.IncludeRespBody),
+		Name:            types.StringValue(plugin.Name),
+		BatchMaxSize:    types.Int64Value(int64(plugin.BatchMaxSize)),
+		InactiveTimeout: types.Int64Value(int64(plugin.InactiveTimeout)),
+		BufferDuration:  types.Int64Value(int64(plugin.BufferDuration)),
+		MaxRetryCount:   types.Int64Value(int64(plugin.MaxRetryCount)),
+		RetryDelay:      types.Int64Value(int64(plugin.RetryDelay)),
+	}
+	for _, broker := range plugin.Brokers {
+		built.Brokers = append(built.Brokers, KafkaBroker{
+			Host: types.StringValue(broker.Host),
+			Port: types.Int64Value(int64(broker.Port)),
+		})
+	}
+	return built
+}
+
+func buildInfraKafkaLoggerPlugin(plugin *KafkaLoggerPlugin) *model.KafkaLoggerPlugin {
+	if plugin == nil {
+		return nil
+	}
+	infraPlugin := &model.KafkaLoggerPlugin{
+		KafkaTopic:      plugin.KafkaTopic.ValueString(),
+		Key:             plugin.Key.ValueString(),
+		Timeout:         int(plugin.Timeout.ValueInt64()),
+		ProducerType:    plugin.ProducerType.ValueString(),
+		RequiredAcks:    int(plugin.RequiredAcks.ValueInt64()),
+		MetaFormat:      plugin.MetaFormat.ValueString(),
+		LogFormat:       plugin.LogFormat,
+		IncludeReqBody:  plugin.IncludeReqBody.ValueBool(),
+		IncludeRespBody: plugin.IncludeRespBody.ValueBool(),
+		Name:            plugin.Name.ValueString(),
+		BatchMaxSize:    int(plugin.BatchMaxSize.ValueInt64()),
+		InactiveTimeout: int(plugin.InactiveTimeout.ValueInt64()),
+		BufferDuration:  int(plugin.BufferDuration.ValueInt64()),
+		MaxRetryCount:   int(plugin.MaxRetryCount.ValueInt64()),
+		RetryDelay:      int(plugin.RetryDelay.ValueInt64()),
+	}
+	for _, broker := range plugin.Brokers {
+		infraPlugin.Brokers = append(infraPlugin.Brokers, model.KafkaBroker{
+			Host: broker.Host.ValueString(),
+			Port: int(broker.Port.ValueInt64()),
+		})
+	}
+	return infraPlugin
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PluginConfigResource{}
+var _ resource.ResourceWithImportState = &PluginConfigResource{}
+var _ resource.ResourceWithConfigValidators = &PluginConfigResource{}
+var _ resource.ResourceWithModifyPlan = &PluginConfigResource{}
+
+func NewPluginConfigResource() resource.Resource {
+	return &PluginConfigResource{}
+}
+
+// PluginConfigResource defines the resource implementation.
+type PluginConfigResource struct {
+	client             *api.ApisixClient
+	clientSecretSource ClientSecretSource
+}
+
+// PluginConfigResourceModel describes the resource data model.
+type PluginConfigResourceModel struct {
+	ID          types.String                    `tfsdk:"id"`
+	Desc        types.String                    `tfsdk:"desc"`
+	Labels      map[string]string               `tfsdk:"labels"`
+	Plugins     *Plugins                        `tfsdk:"plugins"`
+	PluginsJson map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+}
+
+func (r *PluginConfigResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_plugin_config"
+}
+
+func (r *PluginConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "plugin config resource, a reusable plugin bundle referenced by routes with plugin_config_id",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway plugin config ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway plugin config desc",
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Apisix gateway plugin config labels",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"plugins":      pluginsSchema("Apisix gateway plugin config plugins"),
+			"plugins_json": pluginsJsonSchema("Apisix gateway plugin config raw plugins"),
+		},
+	}
+}
+
+func (r *PluginConfigResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		pluginsJsonConflictValidator{},
+	}
+}
+
+func (r *PluginConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.clientSecretSource = providerData.ClientSecretSource
+}
+
+// ModifyPlan reports an openid_connect plugin without a client secret source before apply.
+func (r *PluginConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(validateClientSecretSource(ctx, req.Plan, r.client != nil, r.clientSecretSource)...)
+}
+
+func buildInfraPluginConfig(data *PluginConfigResourceModel, clientSecretSource ClientSecretSource) (*model.PluginConfig, error) {
+	plugins, err := buildInfraPlugins(data.Plugins, data.PluginsJson, clientSecretSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PluginConfig{
+		ID:      data.ID.ValueString(),
+		Desc:    data.Desc.ValueString(),
+		Labels:  data.Labels,
+		Plugins: plugins,
+	}, nil
+}
+
+func fillPluginConfigModel(data *PluginConfigResourceModel, pluginConfig *model.PluginConfig) {
+	data.ID = types.StringValue(pluginConfig.ID)
+	data.Desc = stringValueOrNull(pluginConfig.Desc)
+	data.Labels = pluginConfig.Labels
+	data.Plugins = buildPlugins(pluginConfig.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(pluginConfig.Plugins, data.PluginsJson)
+}
+
+func (r *PluginConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PluginConfigResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	pluginConfig, err := buildInfraPluginConfig(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	createdPluginConfig, err := r.client.CreatePluginConfig(pluginConfig)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugin config",
+			"Could not create plugin config, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the plugin config
+	fillPluginConfigModel(&data, createdPluginConfig)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *PluginConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PluginConfigResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pluginConfig, err := r.client.GetPluginConfigById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting plugin config",
+			"Could not get plugin config, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillPluginConfigModel(&data, pluginConfig)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PluginConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PluginConfigResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	pluginConfig, err := buildInfraPluginConfig(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedPluginConfig, err := r.client.UpdatePluginConfig(pluginConfig)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating plugin config",
+			"Could not update plugin config, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillPluginConfigModel(&data, updatedPluginConfig)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *PluginConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PluginConfigResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeletePluginConfigById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting plugin config",
+			"Could not delete plugin config, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *PluginConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixPluginConfigResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_plugin_config" "auth" {
+    id = "auth"
+    desc = "Auth bundle shared by demo routes"
+    labels = {
+      team = "ssf"
+    }
+    plugins = {
+      openid_connect = {
+        client_id = "client-id"
+        discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
+        required_scopes = ["admin"]
+       }
+    }
+ }
+
+resource "apisix_route" "demo" {
+    id = "demo"
+    uris = ["/api/v1/demo/*"]
+    upstream_id = "1"
+    plugin_config_id = apisix_plugin_config.auth.id
+    name = "demo"
+    desc = "Demo route of the auth plugin config"
+    priority = 0
+    timeout = {
+      connect = 10
+      send = 10
+      read = 10
+    }
+    status = 1
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_plugin_config.auth", "id", "auth"),
+					resource.TestCheckResourceAttr("apisix_plugin_config.auth", "desc", "Auth bundle shared by demo routes"),
+					resource.TestCheckResourceAttr("apisix_plugin_config.auth", "labels.team", "ssf"),
+					resource.TestCheckResourceAttr("apisix_plugin_config.auth", "plugins.openid_connect.client_id", "client-id"),
+					resource.TestCheckResourceAttr("apisix_route.demo", "plugin_config_id", "auth"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_plugin_config.auth",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_plugin_config" "auth" {
+    id = "auth"
+    desc = "Auth bundle shared by demo routes"
+    plugins = {
+      openid_connect = {
+        client_id = "client-id"
+        discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
+        required_scopes = ["admin", "book"]
+       }
+    }
+ }
+
+resource "apisix_route" "demo" {
+    id = "demo"
+    uris = ["/api/v1/demo/*"]
+    upstream_id = "1"
+    plugin_config_id = apisix_plugin_config.auth.id
+    name = "demo"
+    desc = "Demo route of the auth plugin config"
+    priority = 0
+    timeout = {
+      connect = 10
+      send = 10
+      read = 10
+    }
+    status = 1
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("apisix_plugin_config.auth", "labels.team"),
+					resource.TestCheckResourceAttr("apisix_plugin_config.auth", "plugins.openid_connect.required_scopes.1", "book"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
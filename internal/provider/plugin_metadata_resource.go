@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+var pluginNameRegex = regexp.MustCompile(`^[a-z0-9\-]+$`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PluginMetadataResource{}
+var _ resource.ResourceWithImportState = &PluginMetadataResource{}
+
+func NewPluginMetadataResource() resource.Resource {
+	return &PluginMetadataResource{}
+}
+
+// PluginMetadataResource defines the resource implementation.
+type PluginMetadataResource struct {
+	client *api.ApisixClient
+}
+
+// PluginMetadataResourceModel describes the resource data model.
+type PluginMetadataResourceModel struct {
+	PluginName types.String         `tfsdk:"plugin_name"`
+	Metadata   jsontypes.Normalized `tfsdk:"metadata"`
+}
+
+func (r *PluginMetadataResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_plugin_metadata"
+}
+
+func (r *PluginMetadataResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "plugin metadata resource, global settings of a plugin like the log format of http-logger",
+
+		Attributes: map[string]schema.Attribute{
+			"plugin_name": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway plugin name, like http-logger",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(pluginNameRegex, "must be an apisix plugin name, like http-logger"),
+				},
+			},
+			"metadata": schema.StringAttribute{
+				MarkdownDescription: "JSON encoded plugin metadata object, use jsonencode(). Key order and whitespace changes are not a diff",
+				Required:            true,
+				CustomType:          jsontypes.NormalizedType{},
+				Validators: []validator.String{
+					jsonObjectValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (r *PluginMetadataResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func buildInfraPluginMetadata(data *PluginMetadataResourceModel) (*model.PluginMetadata, error) {
+	metadata := make(map[string]any)
+	if err := json.Unmarshal([]byte(data.Metadata.ValueString()), &metadata); err != nil {
+		return nil, fmt.Errorf("metadata must be a JSON object: %w", err)
+	}
+
+	return &model.PluginMetadata{
+		PluginName: data.PluginName.ValueString(),
+		Metadata:   metadata,
+	}, nil
+}
+
+// fillPluginMetadataModel keeps the prior metadata when apisix only added defaults to it, like the level and
+// timeout of error-log-logger, so the filled in defaults are not a diff. The response is taken on import and drift.
+func fillPluginMetadataModel(data *PluginMetadataResourceModel, pluginMetadata *model.PluginMetadata) error {
+	data.PluginName = types.StringValue(pluginMetadata.PluginName)
+
+	if !data.Metadata.IsNull() && !data.Metadata.IsUnknown() {
+		var prior any
+		if err := json.Unmarshal([]byte(data.Metadata.ValueString()), &prior); err == nil && jsonContains(pluginMetadata.Metadata, prior) {
+			return nil
+		}
+	}
+
+	metadata, err := json.Marshal(pluginMetadata.Metadata)
+	if err != nil {
+		return err
+	}
+	data.Metadata = jsontypes.NewNormalizedValue(string(metadata))
+	return nil
+}
+
+func (r *PluginMetadataResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PluginMetadataResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	pluginMetadata, err := buildInfraPluginMetadata(&data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("metadata"),
+			"Invalid plugin metadata",
+			err.Error(),
+		)
+		return
+	}
+
+	createdPluginMetadata, err := r.client.CreatePluginMetadata(pluginMetadata)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugin metadata",
+			"Could not create plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the plugin metadata
+	if err := fillPluginMetadataModel(&data, createdPluginMetadata); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugin metadata",
+			"Could not encode plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource "+data.PluginName.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *PluginMetadataResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PluginMetadataResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pluginMetadata, err := r.client.GetPluginMetadataByName(data.PluginName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting plugin metadata",
+			"Could not get plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if err := fillPluginMetadataModel(&data, pluginMetadata); err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting plugin metadata",
+			"Could not encode plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PluginMetadataResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PluginMetadataResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	pluginMetadata, err := buildInfraPluginMetadata(&data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("metadata"),
+			"Invalid plugin metadata",
+			err.Error(),
+		)
+		return
+	}
+
+	updatedPluginMetadata, err := r.client.UpdatePluginMetadata(pluginMetadata)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating plugin metadata",
+			"Could not update plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if err := fillPluginMetadataModel(&data, updatedPluginMetadata); err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating plugin metadata",
+			"Could not encode plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "updated a resource "+data.PluginName.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *PluginMetadataResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PluginMetadataResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeletePluginMetadataByName(data.PluginName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting plugin metadata",
+			"Could not delete plugin metadata, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *PluginMetadataResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("plugin_name"), req, resp)
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestBuildInfraPluginMetadata(t *testing.T) {
+	data := &PluginMetadataResourceModel{
+		PluginName: types.StringValue("http-logger"),
+		Metadata:   jsontypes.NewNormalizedValue(`{"log_format": {"host": "$host", "client_ip": "$remote_addr"}}`),
+	}
+	pluginMetadata, err := buildInfraPluginMetadata(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pluginMetadata.PluginName != "http-logger" || pluginMetadata.Metadata["log_format"] == nil {
+		t.Errorf("unexpected plugin metadata: %+v", pluginMetadata)
+	}
+
+	data.Metadata = jsontypes.NewNormalizedValue(`["not", "an", "object"]`)
+	if _, err := buildInfraPluginMetadata(data); err == nil {
+		t.Errorf("expected error for JSON array metadata")
+	}
+}
+
+func TestFillPluginMetadataModel(t *testing.T) {
+	configured := `{"host": "127.0.0.1", "port": 1999, "tcp": {"host": "127.0.0.1"}}`
+	// apisix filled in the defaults of level, timeout, keepalive and the batch processor
+	pluginMetadata := &model.PluginMetadata{
+		PluginName: "error-log-logger",
+		Metadata: map[string]any{
+			"host":           "127.0.0.1",
+			"port":           float64(1999),
+			"tcp":            map[string]any{"host": "127.0.0.1", "port": float64(1999), "tls": false},
+			"level":          "WARN",
+			"timeout":        float64(3),
+			"keepalive":      float64(30),
+			"batch_max_size": float64(1000),
+		},
+	}
+
+	data := &PluginMetadataResourceModel{Metadata: jsontypes.NewNormalizedValue(configured)}
+	if err := fillPluginMetadataModel(data, pluginMetadata); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if data.Metadata.ValueString() != configured {
+		t.Errorf("expected the configured metadata to be kept, got %s", data.Metadata)
+	}
+
+	// Changed outside of terraform
+	pluginMetadata.Metadata["level"] = "ERROR"
+	data = &PluginMetadataResourceModel{Metadata: jsontypes.NewNormalizedValue(`{"host": "127.0.0.1", "level": "WARN"}`)}
+	if err := fillPluginMetadataModel(data, pluginMetadata); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(data.Metadata.ValueString(), `"level":"ERROR"`) {
+		t.Errorf("expected the changed level, got %s", data.Metadata)
+	}
+
+	// Import has no prior metadata
+	data = &PluginMetadataResourceModel{Metadata: jsontypes.NewNormalizedNull()}
+	if err := fillPluginMetadataModel(data, pluginMetadata); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if data.PluginName.ValueString() != "error-log-logger" || !strings.Contains(data.Metadata.ValueString(), `"keepalive":30`) {
+		t.Errorf("expected the imported metadata, got %s %s", data.PluginName, data.Metadata)
+	}
+}
+
+func TestJsonObjectValidator(t *testing.T) {
+	cases := map[string]bool{
+		`{"log_format": {"host": "$host"}}`: true,
+		`{}`:                                true,
+		`[]`:                                false,
+		`"log_format"`:                      false,
+		`null`:                              false,
+	}
+
+	for value, valid := range cases {
+		req := validator.StringRequest{Path: path.Root("metadata"), ConfigValue: types.StringValue(value)}
+		resp := &validator.StringResponse{}
+		jsonObjectValidator{}.ValidateString(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestApisixPluginMetadataResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Metadata that is not a JSON object fails at plan time
+			{
+				Config: providerConfig + `
+resource "apisix_plugin_metadata" "http_logger" {
+    plugin_name = "http-logger"
+    metadata = jsonencode([])
+ }
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid JSON object"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_plugin_metadata" "http_logger" {
+    plugin_name = "http-logger"
+    metadata = jsonencode({
+      log_format = {
+        host = "$host"
+        "@timestamp" = "$time_iso8601"
+        client_ip = "$remote_addr"
+      }
+    })
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_plugin_metadata.http_logger", "plugin_name", "http-logger"),
+					resource.TestCheckResourceAttrSet("apisix_plugin_metadata.http_logger", "metadata"),
+				),
+			},
+			// Reordered keys and whitespace must not show a diff
+			{
+				Config: providerConfig + `
+resource "apisix_plugin_metadata" "http_logger" {
+    plugin_name = "http-logger"
+    metadata = <<EOT
+{
+  "log_format": {"client_ip": "$remote_addr", "@timestamp": "$time_iso8601", "host": "$host"}
+}
+EOT
+ }
+`,
+				PlanOnly: true,
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "apisix_plugin_metadata.http_logger",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "plugin_name",
+				ImportStateId:                        "http-logger",
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,342 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// Plugins is the plugin model shared by every apisix object that carries plugins.
+type Plugins struct {
+	OpenIdConnectPlugin   *OpenIdConnectPlugin   `tfsdk:"openid_connect"`
+	LimitReqPlugin        *LimitReqPlugin        `tfsdk:"limit_req"`
+	LimitCountPlugin      *LimitCountPlugin      `tfsdk:"limit_count"`
+	LimitConnPlugin       *LimitConnPlugin       `tfsdk:"limit_conn"`
+	KeyAuthPlugin         *KeyAuthPlugin         `tfsdk:"key_auth"`
+	JwtAuthPlugin         *JwtAuthPlugin         `tfsdk:"jwt_auth"`
+	BasicAuthPlugin       *BasicAuthPlugin       `tfsdk:"basic_auth"`
+	HmacAuthPlugin        *HmacAuthPlugin        `tfsdk:"hmac_auth"`
+	ForwardAuthPlugin     *ForwardAuthPlugin     `tfsdk:"forward_auth"`
+	ProxyRewritePlugin    *ProxyRewritePlugin    `tfsdk:"proxy_rewrite"`
+	ResponseRewritePlugin *ResponseRewritePlugin `tfsdk:"response_rewrite"`
+	RedirectPlugin        *RedirectPlugin        `tfsdk:"redirect"`
+	CorsPlugin            *CorsPlugin            `tfsdk:"cors"`
+	PrometheusPlugin      *PrometheusPlugin      `tfsdk:"prometheus"`
+	ZipkinPlugin          *ZipkinPlugin          `tfsdk:"zipkin"`
+	OpenTelemetryPlugin   *OpenTelemetryPlugin   `tfsdk:"opentelemetry"`
+	SkywalkingPlugin      *SkywalkingPlugin      `tfsdk:"skywalking"`
+	HttpLoggerPlugin      *HttpLoggerPlugin      `tfsdk:"http_logger"`
+	KafkaLoggerPlugin     *KafkaLoggerPlugin     `tfsdk:"kafka_logger"`
+}
+
+type OpenIdConnectPlugin struct {
+	ClientId              string                `tfsdk:"client_id"`
+	Discovery             string                `tfsdk:"discovery"`
+	RequiredScopes        []string              `tfsdk:"required_scopes"`
+	BearerOnly            types.Bool            `tfsdk:"bearer_only"`
+	UseJwks               types.Bool            `tfsdk:"use_jwks"`
+	JwkExpiresIn          types.Int64           `tfsdk:"jwk_expires_in"`
+	AudienceRequired      types.Bool            `tfsdk:"audience_required"`
+	Audience              types.String          `tfsdk:"audience"`
+	AudienceMatchClientId types.Bool            `tfsdk:"audience_match_client_id"`
+	Realm                 types.String          `tfsdk:"realm"`
+	IntrospectionEndpoint types.String          `tfsdk:"introspection_endpoint"`
+	RedirectUri           types.String          `tfsdk:"redirect_uri"`
+	SetUserinfoHeader     types.Bool            `tfsdk:"set_userinfo_header"`
+	Session               *OpenIdConnectSession `tfsdk:"session"`
+}
+
+type OpenIdConnectSession struct {
+	Secret         types.String `tfsdk:"secret"`
+	CookieLifetime types.Int64  `tfsdk:"cookie_lifetime"`
+}
+
+func pluginsSchema(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"openid_connect":   openIdConnectPluginSchema(),
+			"limit_req":        limitReqPluginSchema(),
+			"limit_count":      limitCountPluginSchema(),
+			"limit_conn":       limitConnPluginSchema(),
+			"key_auth":         keyAuthPluginSchema(),
+			"jwt_auth":         jwtAuthPluginSchema(),
+			"basic_auth":       basicAuthPluginSchema(),
+			"hmac_auth":        hmacAuthPluginSchema(),
+			"forward_auth":     forwardAuthPluginSchema(),
+			"proxy_rewrite":    proxyRewritePluginSchema(),
+			"response_rewrite": responseRewritePluginSchema(),
+			"redirect":         redirectPluginSchema(),
+			"cors":             corsPluginSchema(),
+			"prometheus":       prometheusPluginSchema(),
+			"zipkin":           zipkinPluginSchema(),
+			"opentelemetry":    openTelemetryPluginSchema(),
+			"skywalking":       skywalkingPluginSchema(),
+			"http_logger":      httpLoggerPluginSchema(),
+			"kafka_logger":     kafkaLoggerPluginSchema(),
+		},
+		MarkdownDescription: description,
+		Optional:            true,
+	}
+}
+
+// openIdConnectPluginSchema defaults to validating bearer tokens against the JWKS of the realm silas-apisix-gateway.
+func openIdConnectPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "Client ID",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"discovery": schema.StringAttribute{
+				MarkdownDescription: "Discovery endpoint",
+				Optional:            true,
+			},
+			"required_scopes": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Required scopes",
+				Required:            true,
+			},
+			"bearer_only": schema.BoolAttribute{
+				MarkdownDescription: "Only accept bearer tokens, false redirects browsers to the authorization code flow. Default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"use_jwks": schema.BoolAttribute{
+				MarkdownDescription: "Validate bearer tokens against the JWKS of the identity provider instead of introspection. Default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"jwk_expires_in": schema.Int64Attribute{
+				MarkdownDescription: "Seconds the JWKS is cached. Default 600",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(600),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"audience_required": schema.BoolAttribute{
+				MarkdownDescription: "Require the audience claim in tokens. Default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"audience": schema.StringAttribute{
+				MarkdownDescription: "Claim holding the audience. Default aud",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("aud"),
+			},
+			"audience_match_client_id": schema.BoolAttribute{
+				MarkdownDescription: "Require the audience to match the client ID. Default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"realm": schema.StringAttribute{
+				MarkdownDescription: "Realm of the WWW-Authenticate header of rejected requests. Default silas-apisix-gateway",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("silas-apisix-gateway"),
+			},
+			"introspection_endpoint": schema.StringAttribute{
+				MarkdownDescription: "Token introspection endpoint, used when use_jwks is false",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpUrlRegex, "must be a http(s) URL"),
+				},
+			},
+			"redirect_uri": schema.StringAttribute{
+				MarkdownDescription: "URI the identity provider redirects to in the authorization code flow",
+				Optional:            true,
+			},
+			"set_userinfo_header": schema.BoolAttribute{
+				MarkdownDescription: "Pass the user info to the upstream in the X-Userinfo header. Default true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"session": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"secret": schema.StringAttribute{
+						MarkdownDescription: "Secret the session cookie is encrypted with, at least 16 characters",
+						Required:            true,
+						Sensitive:           true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(16),
+						},
+					},
+					"cookie_lifetime": schema.Int64Attribute{
+						MarkdownDescription: "Lifetime in seconds of the session cookie",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+				MarkdownDescription: "Session of the authorization code flow",
+				Optional:            true,
+			},
+		},
+		MarkdownDescription: "openid_connect auth plugin",
+		Optional:            true,
+	}
+}
+
+// buildPlugins converts the plugins returned by apisix, secrets of prior are kept
+// as apisix returns them encrypted when data encryption is enabled.
+func buildPlugins(plugins *model.Plugins, prior *Plugins) *Plugins {
+	if plugins == nil {
+		plugins = &model.Plugins{}
+	}
+	// Keep plugins null when none of them is configured, like when only plugins_json is used
+	configured := prior != nil
+	if prior == nil {
+		prior = &Plugins{}
+	}
+
+	built := &Plugins{
+		OpenIdConnectPlugin:   buildOpenIdConnectPlugin(plugins.OpenIdConnectPlugin, prior.OpenIdConnectPlugin),
+		LimitReqPlugin:        buildLimitReqPlugin(plugins.LimitReqPlugin),
+		LimitCountPlugin:      buildLimitCountPlugin(plugins.LimitCountPlugin, prior.LimitCountPlugin),
+		LimitConnPlugin:       buildLimitConnPlugin(plugins.LimitConnPlugin),
+		KeyAuthPlugin:         buildKeyAuthPlugin(plugins.KeyAuthPlugin),
+		JwtAuthPlugin:         buildJwtAuthPlugin(plugins.JwtAuthPlugin),
+		BasicAuthPlugin:       buildBasicAuthPlugin(plugins.BasicAuthPlugin),
+		HmacAuthPlugin:        buildHmacAuthPlugin(plugins.HmacAuthPlugin, prior.HmacAuthPlugin),
+		ForwardAuthPlugin:     buildForwardAuthPlugin(plugins.ForwardAuthPlugin),
+		ProxyRewritePlugin:    buildProxyRewritePlugin(plugins.ProxyRewritePlugin),
+		ResponseRewritePlugin: buildResponseRewritePlugin(plugins.ResponseRewritePlugin),
+		RedirectPlugin:        buildRedirectPlugin(plugins.RedirectPlugin, prior.RedirectPlugin),
+		CorsPlugin:            buildCorsPlugin(plugins.CorsPlugin),
+		PrometheusPlugin:      buildPrometheusPlugin(plugins.PrometheusPlugin),
+		ZipkinPlugin:          buildZipkinPlugin(plugins.ZipkinPlugin),
+		OpenTelemetryPlugin:   buildOpenTelemetryPlugin(plugins.OpenTelemetryPlugin, prior.OpenTelemetryPlugin),
+		SkywalkingPlugin:      buildSkywalkingPlugin(plugins.SkywalkingPlugin),
+		HttpLoggerPlugin:      buildHttpLoggerPlugin(plugins.HttpLoggerPlugin, prior.HttpLoggerPlugin),
+		KafkaLoggerPlugin:     buildKafkaLoggerPlugin(plugins.KafkaLoggerPlugin),
+	}
+	if !configured && *built == (Plugins{}) {
+		return nil
+	}
+	return built
+}
+
+// buildInfraPlugins merges the typed plugins with the raw plugins of plugins_json.
+func buildInfraPlugins(plugins *Plugins, pluginsJson map[string]jsontypes.Normalized, clientSecretSource ClientSecretSource) (*model.Plugins, error) {
+	extra, err := buildInfraPluginsJson(pluginsJson)
+	if err != nil {
+		return nil, err
+	}
+	if plugins == nil {
+		if extra == nil {
+			return nil, nil
+		}
+		return &model.Plugins{Extra: extra}, nil
+	}
+
+	openIdConnect, err := buildInfraOpenIdConnectPlugin(plugins.OpenIdConnectPlugin, clientSecretSource)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Plugins{
+		OpenIdConnectPlugin:   openIdConnect,
+		LimitReqPlugin:        buildInfraLimitReqPlugin(plugins.LimitReqPlugin),
+		LimitCountPlugin:      buildInfraLimitCountPlugin(plugins.LimitCountPlugin),
+		LimitConnPlugin:       buildInfraLimitConnPlugin(plugins.LimitConnPlugin),
+		KeyAuthPlugin:         buildInfraKeyAuthPlugin(plugins.KeyAuthPlugin),
+		JwtAuthPlugin:         buildInfraJwtAuthPlugin(plugins.JwtAuthPlugin),
+		BasicAuthPlugin:       buildInfraBasicAuthPlugin(plugins.BasicAuthPlugin),
+		HmacAuthPlugin:        buildInfraHmacAuthPlugin(plugins.HmacAuthPlugin),
+		ForwardAuthPlugin:     buildInfraForwardAuthPlugin(plugins.ForwardAuthPlugin),
+		ProxyRewritePlugin:    buildInfraProxyRewritePlugin(plugins.ProxyRewritePlugin),
+		ResponseRewritePlugin: buildInfraResponseRewritePlugin(plugins.ResponseRewritePlugin),
+		RedirectPlugin:        buildInfraRedirectPlugin(plugins.RedirectPlugin),
+		CorsPlugin:            buildInfraCorsPlugin(plugins.CorsPlugin),
+		PrometheusPlugin:      buildInfraPrometheusPlugin(plugins.PrometheusPlugin),
+		ZipkinPlugin:          buildInfraZipkinPlugin(plugins.ZipkinPlugin),
+		OpenTelemetryPlugin:   buildInfraOpenTelemetryPlugin(plugins.OpenTelemetryPlugin),
+		SkywalkingPlugin:      buildInfraSkywalkingPlugin(plugins.SkywalkingPlugin),
+		HttpLoggerPlugin:      buildInfraHttpLoggerPlugin(plugins.HttpLoggerPlugin),
+		KafkaLoggerPlugin:     buildInfraKafkaLoggerPlugin(plugins.KafkaLoggerPlugin),
+		Extra:                 extra,
+	}, nil
+}
+
+func buildOpenIdConnectPlugin(openIdConnect *model.OpenIdConnectPlugin, prior *OpenIdConnectPlugin) *OpenIdConnectPlugin {
+	if openIdConnect == nil {
+		return nil
+	}
+	built := &OpenIdConnectPlugin{
+		ClientId:              openIdConnect.ClientId,
+		Discovery:             openIdConnect.Discovery,
+		RequiredScopes:        openIdConnect.RequiredScopes,
+		BearerOnly:            types.BoolValue(openIdConnect.BearerOnly),
+		UseJwks:               types.BoolValue(openIdConnect.UseJwks),
+		JwkExpiresIn:          types.Int64Value(int64(openIdConnect.JwkExpiresIn)),
+		AudienceRequired:      types.BoolValue(openIdConnect.AudienceRequired),
+		Audience:              types.StringValue(openIdConnect.Audience),
+		AudienceMatchClientId: types.BoolValue(openIdConnect.AudienceMatchClientId),
+		Realm:                 types.StringValue(openIdConnect.Realm),
+		IntrospectionEndpoint: stringValueOrNull(openIdConnect.IntrospectionEndpoint),
+		RedirectUri:           stringValueOrNull(openIdConnect.RedirectUri),
+		SetUserinfoHeader:     types.BoolValue(openIdConnect.SetUserinfoHeader),
+	}
+	if openIdConnect.Session != nil {
+		built.Session = &OpenIdConnectSession{
+			Secret:         types.StringValue(openIdConnect.Session.Secret),
+			CookieLifetime: int64ValueOrNull(openIdConnect.Session.CookieLifetime),
+		}
+		if prior != nil && prior.Session != nil {
+			built.Session.Secret = prior.Session.Secret
+		}
+	}
+	return built
+}
+
+func buildInfraOpenIdConnectPlugin(openIdConnect *OpenIdConnectPlugin, clientSecretSource ClientSecretSource) (*model.OpenIdConnectPlugin, error) {
+	if openIdConnect == nil {
+		return nil, nil
+	}
+	secret, err := fetchClientSecret(clientSecretSource, openIdConnect.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	infraPlugin := &model.OpenIdConnectPlugin{
+		ClientId:              openIdConnect.ClientId,
+		ClientSecret:          secret,
+		Discovery:             openIdConnect.Discovery,
+		RequiredScopes:        openIdConnect.RequiredScopes,
+		BearerOnly:            openIdConnect.BearerOnly.ValueBool(),
+		UseJwks:               openIdConnect.UseJwks.ValueBool(),
+		JwkExpiresIn:          int(openIdConnect.JwkExpiresIn.ValueInt64()),
+		AudienceRequired:      openIdConnect.AudienceRequired.ValueBool(),
+		Audience:              openIdConnect.Audience.ValueString(),
+		AudienceMatchClientId: openIdConnect.AudienceMatchClientId.ValueBool(),
+		Realm:                 openIdConnect.Realm.ValueString(),
+		IntrospectionEndpoint: openIdConnect.IntrospectionEndpoint.ValueString(),
+		RedirectUri:           openIdConnect.RedirectUri.ValueString(),
+		SetUserinfoHeader:     openIdConnect.SetUserinfoHeader.ValueBool(),
+	}
+	if openIdConnect.Session != nil {
+		infraPlugin.Session = &model.OpenIdConnectSession{
+			Secret:         openIdConnect.Session.Secret.ValueString(),
+			CookieLifetime: int(openIdConnect.Session.CookieLifetime.ValueInt64()),
+		}
+	}
+	return infraPlugin, nil
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"silas.com/ssf-terraform/apisix-client/model"
+	"strings"
+)
+
+func pluginsJsonSchema(description string) schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: jsontypes.NormalizedType{},
+		MarkdownDescription: description + ", keyed by apisix plugin name like ip-restriction, the value is the JSON encoded plugin config, use jsonencode(). " +
+			"Merged with plugins, plugins that have a typed counterpart in plugins must be set there",
+		Optional: true,
+		Validators: []validator.Map{
+			mapvalidator.KeysAre(stringvalidator.RegexMatches(pluginNameRegex, "must be an apisix plugin name, like ip-restriction")),
+			mapvalidator.ValueStringsAre(jsonObjectValidator{}),
+		},
+	}
+}
+
+var _ resource.ConfigValidator = pluginsJsonConflictValidator{}
+
+// pluginsJsonConflictValidator rejects plugins_json keys of plugins that have a typed counterpart in plugins. The client
+// decodes those plugins into their typed fields, so set raw they would come back in plugins instead of plugins_json.
+type pluginsJsonConflictValidator struct{}
+
+func (v pluginsJsonConflictValidator) Description(ctx context.Context) string {
+	return "plugins that have a typed counterpart in plugins must not be set in plugins_json"
+}
+
+func (v pluginsJsonConflictValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pluginsJsonConflictValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var pluginsJson types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("plugins_json"), &pluginsJson)...)
+	if resp.Diagnostics.HasError() || pluginsJson.IsNull() || pluginsJson.IsUnknown() {
+		return
+	}
+
+	pluginsType, diags := req.Config.Schema.TypeAtPath(ctx, path.Root("plugins"))
+	resp.Diagnostics.Append(diags...)
+	typedPlugins, ok := pluginsType.(types.ObjectType)
+	if !ok {
+		return
+	}
+	for name := range typedPlugins.AttrTypes {
+		// Typed plugins are named after the apisix plugin with underscores, like limit_req for limit-req
+		pluginName := strings.ReplaceAll(name, "_", "-")
+		if _, ok := pluginsJson.Elements()[pluginName]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("plugins_json").AtMapKey(pluginName),
+				"Conflicting plugin "+pluginName,
+				fmt.Sprintf("Plugin %s has a typed counterpart, set it in plugins.%s instead of plugins_json.", pluginName, name),
+			)
+		}
+	}
+}
+
+// buildInfraPluginsJson decodes the raw plugins, each of them must be a JSON object.
+func buildInfraPluginsJson(pluginsJson map[string]jsontypes.Normalized) (map[string]any, error) {
+	if len(pluginsJson) == 0 {
+		return nil, nil
+	}
+
+	infraPlugins := make(map[string]any, len(pluginsJson))
+	for name, value := range pluginsJson {
+		plugin := make(map[string]any)
+		if err := json.Unmarshal([]byte(value.ValueString()), &plugin); err != nil {
+			return nil, fmt.Errorf("plugins_json %s must be a JSON object: %w", name, err)
+		}
+		infraPlugins[name] = plugin
+	}
+	return infraPlugins, nil
+}
+
+// buildPluginsJson converts the untyped plugins returned by apisix, plugins set outside of terraform show up
+// as drift. The prior value is kept when apisix only added defaults to it, so the filled in defaults are not a diff.
+func buildPluginsJson(plugins *model.Plugins, prior map[string]jsontypes.Normalized) map[string]jsontypes.Normalized {
+	if plugins == nil {
+		return nil
+	}
+	return buildExtraPluginsJson(plugins.Extra, prior)
+}
+
+// buildExtraPluginsJson is buildPluginsJson of the untyped plugins of any plugin-carrying object.
+func buildExtraPluginsJson(extra map[string]any, prior map[string]jsontypes.Normalized) map[string]jsontypes.Normalized {
+	built := make(map[string]jsontypes.Normalized)
+	for name, plugin := range extra {
+		if priorValue, ok := prior[name]; ok {
+			var priorPlugin any
+			if err := json.Unmarshal([]byte(priorValue.ValueString()), &priorPlugin); err == nil && jsonContains(plugin, priorPlugin) {
+				built[name] = priorValue
+				continue
+			}
+		}
+		// Values decoded from the apisix response always encode
+		encoded, _ := json.Marshal(plugin)
+		built[name] = jsontypes.NewNormalizedValue(string(encoded))
+	}
+	if len(built) == 0 {
+		return nil
+	}
+	return built
+}
+
+// jsonContains reports whether every key of expected is in actual with a value containing the expected one.
+func jsonContains(actual any, expected any) bool {
+	expectedObject, ok := expected.(map[string]any)
+	if !ok {
+		return reflect.DeepEqual(actual, expected)
+	}
+	actualObject, ok := actual.(map[string]any)
+	if !ok {
+		return false
+	}
+	for key, value := range expectedObject {
+		actualValue, ok := actualObject[key]
+		if !ok || !jsonContains(actualValue, value) {
+			return false
+		}
+	}
+	return true
+}
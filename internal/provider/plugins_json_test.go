@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+func TestBuildPluginsJson(t *testing.T) {
+	prior := map[string]jsontypes.Normalized{
+		"real-ip":      jsontypes.NewNormalizedValue(`{"source": "http_x_forwarded_for"}`),
+		"proxy-mirror": jsontypes.NewNormalizedValue(`{"host": "http://127.0.0.1:9797"}`),
+	}
+	plugins := &model.Plugins{
+		Extra: map[string]any{
+			// apisix filled in the default of recursive
+			"real-ip": map[string]any{"source": "http_x_forwarded_for", "recursive": false},
+			// changed outside of terraform
+			"proxy-mirror": map[string]any{"host": "http://127.0.0.1:9898"},
+			// added outside of terraform
+			"request-id": map[string]any{"header_name": "X-Request-Id"},
+		},
+	}
+
+	built := buildPluginsJson(plugins, prior)
+	if built["real-ip"].ValueString() != `{"source": "http_x_forwarded_for"}` {
+		t.Errorf("expected the prior real-ip to be kept, got %s", built["real-ip"])
+	}
+	if built["proxy-mirror"].ValueString() != `{"host":"http://127.0.0.1:9898"}` {
+		t.Errorf("expected the changed proxy-mirror, got %s", built["proxy-mirror"])
+	}
+	if built["request-id"].ValueString() != `{"header_name":"X-Request-Id"}` {
+		t.Errorf("expected the added request-id, got %s", built["request-id"])
+	}
+
+	if built := buildPluginsJson(&model.Plugins{}, nil); built != nil {
+		t.Errorf("expected nil without untyped plugins, got %v", built)
+	}
+}
+
+func TestBuildInfraPluginsJson(t *testing.T) {
+	if _, err := buildInfraPluginsJson(map[string]jsontypes.Normalized{"real-ip": jsontypes.NewNormalizedValue(`["not", "an", "object"]`)}); err == nil {
+		t.Error("expected an error of a plugin that is not a JSON object")
+	}
+
+	infraPlugins, err := buildInfraPluginsJson(map[string]jsontypes.Normalized{"real-ip": jsontypes.NewNormalizedValue(`{"source": "http_x_forwarded_for"}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if infraPlugins["real-ip"].(map[string]any)["source"] != "http_x_forwarded_for" {
+		t.Errorf("unexpected plugins %v", infraPlugins)
+	}
+}
+
+// pluginsJsonConfig is a config of the resource r with only plugins_json set.
+func pluginsJsonConfig(t *testing.T, r resource.Resource, pluginsJson map[string]string) tfsdk.Config {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+	objectType := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	elements := make(map[string]tftypes.Value, len(pluginsJson))
+	for name, value := range pluginsJson {
+		elements[name] = tftypes.NewValue(tftypes.String, value)
+	}
+	values["plugins_json"] = tftypes.NewValue(objectType.AttributeTypes["plugins_json"], elements)
+
+	return tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+}
+
+func TestPluginsJsonConflictValidator(t *testing.T) {
+	cases := map[string]struct {
+		resource resource.Resource
+		plugin   string
+		valid    bool
+	}{
+		"route typed plugin":          {NewRouteResource(), "limit-req", false},
+		"route raw plugin":            {NewRouteResource(), "real-ip", true},
+		"global rule typed plugin":    {NewGlobalRuleResource(), "proxy-rewrite", false},
+		"stream route typed plugin":   {NewStreamRouteResource(), "limit-conn", false},
+		"stream route raw plugin":     {NewStreamRouteResource(), "limit-req", true},
+		"consumer typed plugin":       {NewConsumerResource(), "key-auth", false},
+		"consumer raw plugin":         {NewConsumerResource(), "limit-count", true},
+		"consumer group typed plugin": {NewConsumerGroupResource(), "limit-count", false},
+	}
+
+	for name, c := range cases {
+		req := resource.ValidateConfigRequest{Config: pluginsJsonConfig(t, c.resource, map[string]string{c.plugin: `{}`})}
+		resp := &resource.ValidateConfigResponse{}
+		pluginsJsonConflictValidator{}.ValidateResource(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", name, c.valid, resp.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const EnvApisixProfilesFile = "APISIX_PROFILES_FILE"
+
+// GatewayProfile holds the admin api settings of one apisix gateway env, like dev, uat or prod.
+type GatewayProfile struct {
+	AdminUrl           string `yaml:"admin_url"`
+	AdminKey           string `yaml:"admin_key"`
+	CaCert             string `yaml:"ca_cert"`
+	ClientCert         string `yaml:"client_cert"`
+	ClientKey          string `yaml:"client_key"`
+	InsecureSkipVerify *bool  `yaml:"insecure_skip_verify"`
+	RequestTimeout     *int64 `yaml:"request_timeout"`
+}
+
+// GatewayProfiles describes the profiles file, for example:
+//
+//	profiles:
+//	  dev:
+//	    admin_url: http://apisix-dev:9180
+//	    admin_key: edd1c9f034335f136f87ad84b625c8f1
+type GatewayProfiles struct {
+	Profiles map[string]GatewayProfile `yaml:"profiles"`
+}
+
+func defaultProfilesFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".apisix", "profiles.yaml"), nil
+}
+
+func loadProfile(file string, env string) (*GatewayProfile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read profiles file %s: %w", file, err)
+	}
+
+	var profiles GatewayProfiles
+	if err := yaml.Unmarshal(content, &profiles); err != nil {
+		return nil, fmt.Errorf("could not parse profiles file %s: %w", file, err)
+	}
+
+	profile, ok := profiles.Profiles[env]
+	if !ok {
+		names := make([]string, 0, len(profiles.Profiles))
+		for name := range profiles.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("profile %q not found in %s, available profiles: [%s]", env, file, strings.Join(names, ", "))
+	}
+
+	return &profile, nil
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testProfiles = `
+profiles:
+  dev:
+    admin_url: http://apisix-dev:9180
+    admin_key: dev-key
+  uat:
+    admin_url: https://apisix-uat:9180
+    admin_key: uat-key
+    insecure_skip_verify: true
+    request_timeout: 60
+`
+
+func TestLoadProfile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(file, []byte(testProfiles), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := loadProfile(file, "uat")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if profile.AdminUrl != "https://apisix-uat:9180" || profile.AdminKey != "uat-key" {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+	if profile.InsecureSkipVerify == nil || !*profile.InsecureSkipVerify {
+		t.Errorf("expected insecure_skip_verify to be true")
+	}
+	if profile.RequestTimeout == nil || *profile.RequestTimeout != 60 {
+		t.Errorf("expected request_timeout to be 60")
+	}
+
+	profile, err = loadProfile(file, "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if profile.InsecureSkipVerify != nil || profile.RequestTimeout != nil {
+		t.Errorf("expected unset defaults, got: %+v", profile)
+	}
+
+	_, err = loadProfile(file, "prod")
+	if err == nil || !strings.Contains(err.Error(), "available profiles: [dev, uat]") {
+		t.Errorf("expected missing profile error, got: %v", err)
+	}
+
+	_, err = loadProfile(filepath.Join(t.TempDir(), "missing.yaml"), "dev")
+	if err == nil {
+		t.Errorf("expected error for missing profiles file")
+	}
+}
+
+func TestResolveString(t *testing.T) {
+	const key = "APISIX_TEST_RESOLVE_STRING"
+
+	tests := []struct {
+		name    string
+		value   types.String
+		profile string
+		env     string
+		want    string
+	}{
+		{"config over profile and env", types.StringValue("config"), "profile", "env", "config"},
+		{"empty config over profile and env", types.StringValue(""), "profile", "env", ""},
+		{"profile over env", types.StringNull(), "profile", "env", "profile"},
+		{"env", types.StringNull(), "", "env", "env"},
+		{"unset", types.StringNull(), "", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(key, test.env)
+			if got := resolveString(test.value, test.profile, key); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	cert, key := generateCertificate(t, time.Now().Add(time.Hour), "client.example.com")
+	otherCert, _ := generateCertificate(t, time.Now().Add(time.Hour), "other.example.com")
+
+	tests := []struct {
+		name       string
+		caCert     string
+		clientCert string
+		clientKey  string
+		wantErr    string
+	}{
+		{name: "empty"},
+		{name: "ca and client pair", caCert: cert, clientCert: cert, clientKey: key},
+		{name: "bad ca", caCert: "not a certificate", wantErr: "ca_cert does not contain any valid PEM encoded certificate"},
+		{name: "client cert without key", clientCert: cert, wantErr: "client_cert and client_key must be set together"},
+		{name: "client key without cert", clientKey: key, wantErr: "client_cert and client_key must be set together"},
+		{name: "mismatched client pair", clientCert: otherCert, clientKey: key, wantErr: "private key does not match public key"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tlsConfig, err := buildTLSConfig(test.caCert, test.clientCert, test.clientKey, true)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error %q, got: %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !tlsConfig.InsecureSkipVerify {
+				t.Errorf("expected insecure_skip_verify to be kept")
+			}
+			if (test.caCert != "") != (tlsConfig.RootCAs != nil) {
+				t.Errorf("unexpected root CAs: %v", tlsConfig.RootCAs)
+			}
+			if (test.clientCert != "") != (len(tlsConfig.Certificates) == 1) {
+				t.Errorf("unexpected client certificates: %d", len(tlsConfig.Certificates))
+			}
+		})
+	}
+}
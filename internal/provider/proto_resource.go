@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// protoFileName names the content in parse errors, like content.proto:3:1: syntax error.
+const protoFileName = "content.proto"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ProtoResource{}
+var _ resource.ResourceWithImportState = &ProtoResource{}
+var _ resource.ResourceWithValidateConfig = &ProtoResource{}
+var _ resource.ResourceWithModifyPlan = &ProtoResource{}
+
+func NewProtoResource() resource.Resource {
+	return &ProtoResource{}
+}
+
+// ProtoResource defines the resource implementation.
+type ProtoResource struct {
+	client *api.ApisixClient
+}
+
+// ProtoResourceModel describes the resource data model.
+type ProtoResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Desc     types.String `tfsdk:"desc"`
+	Content  types.String `tfsdk:"content"`
+	Services types.List   `tfsdk:"services"`
+	Methods  types.List   `tfsdk:"methods"`
+}
+
+func (r *ProtoResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_proto"
+}
+
+func (r *ProtoResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "proto resource, protobuf definitions used by the grpc-transcode plugin",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway proto ID, referenced by proto_id of grpc-transcode",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway proto desc",
+				Optional:            true,
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Protobuf definitions, use file(). Only well-known google/protobuf imports are resolved",
+				Required:            true,
+			},
+			"services": schema.ListAttribute{
+				MarkdownDescription: "Fully qualified names of the services defined by content",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"methods": schema.ListAttribute{
+				MarkdownDescription: "Methods defined by content, like helloworld.Greeter/SayHello",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *ProtoResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+// parseProto parses the protobuf definitions and returns the services and methods they define.
+func parseProto(content string) ([]string, []string, error) {
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{protoFileName: content}),
+	}
+	files, err := parser.ParseFiles(protoFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	services := make([]string, 0)
+	methods := make([]string, 0)
+	for _, service := range files[0].GetServices() {
+		services = append(services, service.GetFullyQualifiedName())
+		for _, method := range service.GetMethods() {
+			methods = append(methods, service.GetFullyQualifiedName()+"/"+method.GetName())
+		}
+	}
+	return services, methods, nil
+}
+
+func (r *ProtoResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var content types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content"), &content)...)
+	if resp.Diagnostics.HasError() || content.IsUnknown() || content.IsNull() {
+		return
+	}
+
+	if _, _, err := parseProto(content.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Invalid protobuf definitions",
+			"Could not parse content, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// ModifyPlan fills the services and methods of the content into the plan, so they are known before apply.
+func (r *ProtoResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var content types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("content"), &content)...)
+	if resp.Diagnostics.HasError() || content.IsUnknown() {
+		return
+	}
+
+	services, methods, err := parseProto(content.ValueString())
+	if err != nil {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("services"), services)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("methods"), methods)...)
+}
+
+func buildInfraProto(data *ProtoResourceModel) *model.Proto {
+	return &model.Proto{
+		ID:      data.ID.ValueString(),
+		Desc:    data.Desc.ValueString(),
+		Content: data.Content.ValueString(),
+	}
+}
+
+func fillProtoModel(ctx context.Context, data *ProtoResourceModel, proto *model.Proto) diag.Diagnostics {
+	var diags diag.Diagnostics
+	data.ID = types.StringValue(proto.ID)
+	data.Desc = stringValueOrNull(proto.Desc)
+	data.Content = types.StringValue(proto.Content)
+
+	data.Services = types.ListNull(types.StringType)
+	data.Methods = types.ListNull(types.StringType)
+	if services, methods, err := parseProto(proto.Content); err == nil {
+		var d diag.Diagnostics
+		data.Services, d = types.ListValueFrom(ctx, types.StringType, services)
+		diags.Append(d...)
+		data.Methods, d = types.ListValueFrom(ctx, types.StringType, methods)
+		diags.Append(d...)
+	}
+	return diags
+}
+
+func (r *ProtoResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ProtoResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createdProto, err := r.client.CreateProto(buildInfraProto(&data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating proto",
+			"Could not create proto, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the proto
+	resp.Diagnostics.Append(fillProtoModel(ctx, &data, createdProto)...)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ProtoResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ProtoResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	proto, err := r.client.GetProtoById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting proto",
+			"Could not get proto, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(fillProtoModel(ctx, &data, proto)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProtoResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ProtoResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updatedProto, err := r.client.UpdateProto(buildInfraProto(&data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating proto",
+			"Could not update proto, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(fillProtoModel(ctx, &data, updatedProto)...)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ProtoResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ProtoResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteProtoById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting proto",
+			"Could not delete proto, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *ProtoResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testGreeterProto = `syntax = "proto3";
+
+package helloworld;
+
+import "google/protobuf/empty.proto";
+
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply) {}
+  rpc Ping (google.protobuf.Empty) returns (google.protobuf.Empty) {}
+}
+
+message HelloRequest {
+  string name = 1;
+}
+
+message HelloReply {
+  string message = 1;
+}
+`
+
+func TestApisixProtoResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Syntax errors are rejected at plan time
+			{
+				Config: providerConfig + `
+resource "apisix_proto" "greeter" {
+    id = "greeter"
+    content = "syntax = \"proto3\"; service Greeter {"
+ }
+`,
+				ExpectError: regexp.MustCompile("Invalid protobuf definitions"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_proto" "greeter" {
+    id = "greeter"
+    desc = "Greeter service"
+    content = <<EOT
+` + testGreeterProto + `EOT
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_proto.greeter", "id", "greeter"),
+					resource.TestCheckResourceAttr("apisix_proto.greeter", "services.0", "helloworld.Greeter"),
+					resource.TestCheckResourceAttr("apisix_proto.greeter", "methods.#", "2"),
+					resource.TestCheckResourceAttr("apisix_proto.greeter", "methods.0", "helloworld.Greeter/SayHello"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_proto.greeter",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_proto" "greeter" {
+    id = "greeter"
+    content = <<EOT
+syntax = "proto3";
+package helloworld;
+EOT
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("apisix_proto.greeter", "desc"),
+					resource.TestCheckResourceAttr("apisix_proto.greeter", "services.#", "0"),
+					resource.TestCheckResourceAttr("apisix_proto.greeter", "methods.#", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestParseProto(t *testing.T) {
+	services, methods, err := parseProto(testGreeterProto)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(services, []string{"helloworld.Greeter"}) {
+		t.Errorf("unexpected services %v", services)
+	}
+	if !reflect.DeepEqual(methods, []string{"helloworld.Greeter/SayHello", "helloworld.Greeter/Ping"}) {
+		t.Errorf("unexpected methods %v", methods)
+	}
+
+	if _, _, err := parseProto(`syntax = "proto3"; message Broken {`); err == nil {
+		t.Error("expected a syntax error")
+	}
+	if _, _, err := parseProto(`syntax = "proto3"; import "missing.proto";`); err == nil {
+		t.Error("expected an unresolved import error")
+	}
+}
@@ -5,10 +5,20 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"os"
 	"silas.com/ssf-terraform/apisix-client/api"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
@@ -18,6 +28,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const (
+	EnvApisixCaCert             = "APISIX_CA_CERT"
+	EnvApisixClientCert         = "APISIX_CLIENT_CERT"
+	EnvApisixClientKey          = "APISIX_CLIENT_KEY"
+	EnvApisixInsecureSkipVerify = "APISIX_INSECURE_SKIP_VERIFY"
+	EnvApisixRequestTimeout     = "APISIX_REQUEST_TIMEOUT"
+)
+
+const defaultRequestTimeout = 30
+
 // Ensure ApisixGatewayProvider satisfies various provider interfaces.
 var _ provider.Provider = &ApisixGatewayProvider{}
 var _ provider.ProviderWithFunctions = &ApisixGatewayProvider{}
@@ -33,7 +53,22 @@ type ApisixGatewayProvider struct {
 
 // ApisixGatewayProviderModel describes the provider data model.
 type ApisixGatewayProviderModel struct {
-	Env types.String `tfsdk:"env"`
+	Env                types.String             `tfsdk:"env"`
+	ProfilesFile       types.String             `tfsdk:"profiles_file"`
+	AdminUrl           types.String             `tfsdk:"admin_url"`
+	AdminKey           types.String             `tfsdk:"admin_key"`
+	CaCert             types.String             `tfsdk:"ca_cert"`
+	ClientCert         types.String             `tfsdk:"client_cert"`
+	ClientKey          types.String             `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool               `tfsdk:"insecure_skip_verify"`
+	RequestTimeout     types.Int64              `tfsdk:"request_timeout"`
+	ClientSecretSource *ClientSecretSourceModel `tfsdk:"client_secret_source"`
+}
+
+// ApisixProviderData is handed to every resource by the provider.
+type ApisixProviderData struct {
+	Client             *api.ApisixClient
+	ClientSecretSource ClientSecretSource
 }
 
 func (p *ApisixGatewayProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -45,13 +80,119 @@ func (p *ApisixGatewayProvider) Schema(ctx context.Context, req provider.SchemaR
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"env": schema.StringAttribute{
-				MarkdownDescription: "apisix gateway running env, like dev,uat",
+				MarkdownDescription: "apisix gateway running env, like dev,uat. Selects the profile of the same name from 'profiles_file'",
+				Optional:            true,
+			},
+			"profiles_file": schema.StringAttribute{
+				MarkdownDescription: "Path of the profiles file, default ~/.apisix/profiles.yaml. Falls back to env 'APISIX_PROFILES_FILE'",
+				Optional:            true,
+			},
+			"admin_url": schema.StringAttribute{
+				MarkdownDescription: "apisix gateway admin api addr, like http://127.0.0.1:9180. Falls back to env 'APISIX_HOST'",
+				Optional:            true,
+			},
+			"admin_key": schema.StringAttribute{
+				MarkdownDescription: "apisix gateway admin api key. Falls back to env 'APISIX_KEY'",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificate used to verify the admin api. Falls back to env 'APISIX_CA_CERT'",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate for mTLS to the admin api. Falls back to env 'APISIX_CLIENT_CERT'",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client private key for mTLS to the admin api. Falls back to env 'APISIX_CLIENT_KEY'",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip TLS verification of the admin api. Falls back to env 'APISIX_INSECURE_SKIP_VERIFY'",
+				Optional:            true,
+			},
+			"request_timeout": schema.Int64Attribute{
+				MarkdownDescription: "Timeout in seconds of each admin api request, default 30. Falls back to env 'APISIX_REQUEST_TIMEOUT'",
 				Optional:            true,
 			},
+			"client_secret_source": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"client_secret": schema.StringAttribute{
+						MarkdownDescription: "Client secret set inline",
+						Optional:            true,
+						Sensitive:           true,
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(
+								path.MatchRelative().AtParent().AtName("env"),
+								path.MatchRelative().AtParent().AtName("file"),
+								path.MatchRelative().AtParent().AtName("reference"),
+							),
+						},
+					},
+					"env": schema.StringAttribute{
+						MarkdownDescription: "Env the client secret is read from when the plugin is applied, like OIDC_SECRET_{client_id}",
+						Optional:            true,
+					},
+					"file": schema.StringAttribute{
+						MarkdownDescription: "File the client secret is read from when the plugin is applied, like /run/secrets/{client_id}",
+						Optional:            true,
+					},
+					"reference": schema.StringAttribute{
+						MarkdownDescription: "Apisix `$secret://` or `$env://` reference passed through to apisix, like $secret://vault/oidc/{client_id}",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(clientSecretReferenceRegex, "must be a $secret:// or $env:// reference"),
+						},
+					},
+				},
+				MarkdownDescription: "Source of the client secret of openid_connect plugins, exactly one of them must be set. " +
+					"{client_id} in env, file and reference is replaced by the client ID of the plugin",
+				Optional: true,
+			},
 		},
 	}
 }
 
+// resolveString returns the configured value, then the profile value, then the value of env key.
+func resolveString(value types.String, profileValue string, key string) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+	if profileValue != "" {
+		return profileValue
+	}
+	return os.Getenv(key)
+}
+
+func buildTLSConfig(caCert string, clientCert string, clientKey string, insecureSkipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("ca_cert does not contain any valid PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (clientCert == "") != (clientKey == "") {
+		return nil, errors.New("client_cert and client_key must be set together")
+	}
+	if clientCert != "" {
+		certificate, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
 func (p *ApisixGatewayProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data ApisixGatewayProviderModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -59,18 +200,150 @@ func (p *ApisixGatewayProvider) Configure(ctx context.Context, req provider.Conf
 		return
 	}
 
-	host, ok := os.LookupEnv(api.ApisixHost)
-	if !ok || host == "" {
-		resp.Diagnostics.AddError(
-			"Env 'APISIX_HOST' not set",
-			"User must set env 'APISIX_HOST', it represent the addr of apisix gateway.",
+	values := map[string]attr.Value{
+		"env":                  data.Env,
+		"profiles_file":        data.ProfilesFile,
+		"admin_url":            data.AdminUrl,
+		"admin_key":            data.AdminKey,
+		"ca_cert":              data.CaCert,
+		"client_cert":          data.ClientCert,
+		"client_key":           data.ClientKey,
+		"insecure_skip_verify": data.InsecureSkipVerify,
+		"request_timeout":      data.RequestTimeout,
+	}
+	if data.ClientSecretSource != nil {
+		values["client_secret_source.client_secret"] = data.ClientSecretSource.ClientSecret
+		values["client_secret_source.env"] = data.ClientSecretSource.Env
+		values["client_secret_source.file"] = data.ClientSecretSource.File
+		values["client_secret_source.reference"] = data.ClientSecretSource.Reference
+	}
+	for name, value := range values {
+		if value.IsUnknown() {
+			attributePath := path.Root(name)
+			if parent, child, nested := strings.Cut(name, "."); nested {
+				attributePath = path.Root(parent).AtName(child)
+			}
+			resp.Diagnostics.AddAttributeError(
+				attributePath,
+				"Unknown provider attribute '"+name+"'",
+				"The provider cannot create the apisix client as there is an unknown configuration value for '"+name+"'. "+
+					"Either target apply the source of the value first, set the value statically in the configuration, or use the env variable.",
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	profile := &GatewayProfile{}
+	if data.Env.ValueString() != "" {
+		profilesFile := data.ProfilesFile.ValueString()
+		if profilesFile == "" {
+			profilesFile = os.Getenv(EnvApisixProfilesFile)
+		}
+		if profilesFile == "" {
+			defaultFile, err := defaultProfilesFile()
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("profiles_file"),
+					"Unable to locate profiles file",
+					"Could not resolve the default profiles file, set 'profiles_file' instead: "+err.Error(),
+				)
+				return
+			}
+			profilesFile = defaultFile
+		}
+
+		loadedProfile, err := loadProfile(profilesFile, data.Env.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("env"),
+				"Apisix gateway profile '"+data.Env.ValueString()+"' not available",
+				"The provider selects the admin api settings by 'env', unexpected error: "+err.Error(),
+			)
+			return
+		}
+		profile = loadedProfile
+	}
+
+	host := resolveString(data.AdminUrl, profile.AdminUrl, api.ApisixHost)
+	if host == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("admin_url"),
+			"Missing apisix admin url",
+			"User must set 'admin_url', the 'admin_url' of the 'env' profile or env 'APISIX_HOST', it represent the addr of apisix gateway.",
 		)
 	}
-	key, ok := os.LookupEnv(api.ApisixKey)
-	if !ok || key == "" {
+	key := resolveString(data.AdminKey, profile.AdminKey, api.ApisixKey)
+	if key == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("admin_key"),
+			"Missing apisix admin key",
+			"User must set 'admin_key', the 'admin_key' of the 'env' profile or env 'APISIX_KEY', it contains the authentication info of apisix gateway.",
+		)
+	}
+
+	insecureSkipVerify := data.InsecureSkipVerify.ValueBool()
+	if data.InsecureSkipVerify.IsNull() && profile.InsecureSkipVerify != nil {
+		insecureSkipVerify = *profile.InsecureSkipVerify
+	} else if data.InsecureSkipVerify.IsNull() {
+		if value, ok := os.LookupEnv(EnvApisixInsecureSkipVerify); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Invalid env 'APISIX_INSECURE_SKIP_VERIFY'",
+					"Env 'APISIX_INSECURE_SKIP_VERIFY' must be a boolean: "+err.Error(),
+				)
+			}
+			insecureSkipVerify = parsed
+		}
+	}
+
+	timeout := int64(defaultRequestTimeout)
+	if !data.RequestTimeout.IsNull() {
+		timeout = data.RequestTimeout.ValueInt64()
+		if timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid request timeout",
+				"'request_timeout' must be greater than 0.",
+			)
+		}
+	} else if profile.RequestTimeout != nil {
+		timeout = *profile.RequestTimeout
+		if timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("env"),
+				"Invalid request timeout",
+				"The 'request_timeout' of the 'env' profile must be greater than 0.",
+			)
+		}
+	} else if value, ok := os.LookupEnv(EnvApisixRequestTimeout); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid env 'APISIX_REQUEST_TIMEOUT'",
+				"Env 'APISIX_REQUEST_TIMEOUT' must be an integer of seconds: "+err.Error(),
+			)
+		} else if parsed <= 0 {
+			resp.Diagnostics.AddError(
+				"Invalid env 'APISIX_REQUEST_TIMEOUT'",
+				"Env 'APISIX_REQUEST_TIMEOUT' must be greater than 0.",
+			)
+		}
+		timeout = parsed
+	}
+
+	tlsConfig, err := buildTLSConfig(
+		resolveString(data.CaCert, profile.CaCert, EnvApisixCaCert),
+		resolveString(data.ClientCert, profile.ClientCert, EnvApisixClientCert),
+		resolveString(data.ClientKey, profile.ClientKey, EnvApisixClientKey),
+		insecureSkipVerify,
+	)
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Env 'APISIX_KEY' not set",
-			"User must set env 'APISIX_KEY', it contains the authentication info of apisix gateway.",
+			"Invalid apisix admin api TLS settings",
+			"Could not build TLS config, unexpected error: "+err.Error(),
 		)
 	}
 
@@ -83,9 +356,18 @@ func (p *ApisixGatewayProvider) Configure(ctx context.Context, req provider.Conf
 	ctx = tflog.SetField(ctx, "apisix_gateway_key", key)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "apisix_gateway_key")
 
-	client := api.NewApisixClient()
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	client := api.NewApisixClientWithConfig(&api.Config{
+		Host:      host,
+		Key:       key,
+		TLSConfig: tlsConfig,
+		Timeout:   time.Duration(timeout) * time.Second,
+	})
+	providerData := &ApisixProviderData{
+		Client:             client,
+		ClientSecretSource: newClientSecretSource(data.ClientSecretSource),
+	}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 
 	tflog.Info(ctx, "Configured Apisix Client", map[string]any{"success": true})
 }
@@ -94,6 +376,17 @@ func (p *ApisixGatewayProvider) Resources(ctx context.Context) []func() resource
 	return []func() resource.Resource{
 		NewRouteResource,
 		NewUpstreamResource,
+		NewServiceResource,
+		NewConsumerResource,
+		NewConsumerCredentialResource,
+		NewConsumerGroupResource,
+		NewSSLResource,
+		NewGlobalRuleResource,
+		NewPluginConfigResource,
+		NewPluginMetadataResource,
+		NewStreamRouteResource,
+		NewSecretResource,
+		NewProtoResource,
 	}
 }
 
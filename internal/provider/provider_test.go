@@ -10,12 +10,14 @@ import (
 
 const (
 	// providerConfig is a shared configuration to combine with the actual
-	// test configuration so the HashiCups client is properly configured.
-	// It is also possible to use the HASHICUPS_ environment variables instead,
-	// such as updating the Makefile and running the testing through that tool.
+	// test configuration so the apisix client is properly configured.
+	// No 'env' is set, so the admin api settings come from the APISIX_
+	// environment variables the tests set instead of a profiles file.
 	providerConfig = `
 provider "apisix" {
-  env = "local"
+  client_secret_source = {
+    client_secret = "client_secret"
+  }
 }
 `
 )
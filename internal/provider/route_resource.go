@@ -11,6 +11,7 @@ import (
 	"silas.com/ssf-terraform/apisix-client/api"
 	"silas.com/ssf-terraform/apisix-client/model"
 
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -27,6 +28,8 @@ const (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &RouteResource{}
 var _ resource.ResourceWithImportState = &RouteResource{}
+var _ resource.ResourceWithConfigValidators = &RouteResource{}
+var _ resource.ResourceWithModifyPlan = &RouteResource{}
 
 func NewRouteResource() resource.Resource {
 	return &RouteResource{}
@@ -34,34 +37,28 @@ func NewRouteResource() resource.Resource {
 
 // RouteResource defines the resource implementation.
 type RouteResource struct {
-	client *api.ApisixClient
+	client             *api.ApisixClient
+	clientSecretSource ClientSecretSource
 }
 
 // RouteResourceModel describes the resource data model.
 type RouteResourceModel struct {
-	ID         types.String      `tfsdk:"id"`
-	Uris       []string          `tfsdk:"uris"`
-	UpstreamId types.String      `tfsdk:"upstream_id"`
-	Plugins    *Plugins          `tfsdk:"plugins"`
-	Name       types.String      `tfsdk:"name"`
-	Desc       types.String      `tfsdk:"desc"`
-	Hosts      []string          `tfsdk:"hosts"`
-	Methods    []string          `tfsdk:"methods"`
-	Priority   types.Int32       `tfsdk:"priority"`
-	Vars       [][]string        `tfsdk:"vars"`
-	Labels     map[string]string `tfsdk:"labels"`
-	Timeout    *Timeout          `tfsdk:"timeout"`
-	Status     types.Int32       `tfsdk:"status"`
-}
-
-type Plugins struct {
-	OpenIdConnectPlugin *OpenIdConnectPlugin `tfsdk:"openid_connect"`
-}
-
-type OpenIdConnectPlugin struct {
-	ClientId       string   `tfsdk:"client_id"`
-	Discovery      string   `tfsdk:"discovery"`
-	RequiredScopes []string `tfsdk:"required_scopes"`
+	ID             types.String                    `tfsdk:"id"`
+	Uris           []string                        `tfsdk:"uris"`
+	UpstreamId     types.String                    `tfsdk:"upstream_id"`
+	ServiceId      types.String                    `tfsdk:"service_id"`
+	PluginConfigId types.String                    `tfsdk:"plugin_config_id"`
+	Plugins        *Plugins                        `tfsdk:"plugins"`
+	PluginsJson    map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+	Name           types.String                    `tfsdk:"name"`
+	Desc           types.String                    `tfsdk:"desc"`
+	Hosts          []string                        `tfsdk:"hosts"`
+	Methods        []string                        `tfsdk:"methods"`
+	Priority       types.Int32                     `tfsdk:"priority"`
+	Vars           [][]string                      `tfsdk:"vars"`
+	Labels         map[string]string               `tfsdk:"labels"`
+	Timeout        *Timeout                        `tfsdk:"timeout"`
+	Status         types.Int32                     `tfsdk:"status"`
 }
 
 type Timeout struct {
@@ -96,34 +93,16 @@ func (r *RouteResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "Apisix gateway route upstream ID",
 				Optional:            true,
 			},
-			"plugins": schema.SingleNestedAttribute{
-				Attributes: map[string]schema.Attribute{
-					"openid_connect": schema.SingleNestedAttribute{
-						Attributes: map[string]schema.Attribute{
-							"client_id": schema.StringAttribute{
-								MarkdownDescription: "Client ID",
-								Optional:            true,
-								PlanModifiers: []planmodifier.String{
-									stringplanmodifier.UseStateForUnknown(),
-								},
-							},
-							"discovery": schema.StringAttribute{
-								MarkdownDescription: "Discovery endpoint",
-								Optional:            true,
-							},
-							"required_scopes": schema.ListAttribute{
-								ElementType:         types.StringType,
-								MarkdownDescription: "Required scopes",
-								Required:            true,
-							},
-						},
-						MarkdownDescription: "openid_connect auth plugin",
-						Optional:            true,
-					},
-				},
-				MarkdownDescription: "Apisix gateway route plugins",
+			"service_id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway route service ID",
+				Optional:            true,
+			},
+			"plugin_config_id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway route plugin config ID, its plugins are merged with plugins of the route",
 				Optional:            true,
 			},
+			"plugins":      pluginsSchema("Apisix gateway route plugins"),
+			"plugins_json": pluginsJsonSchema("Apisix gateway route raw plugins"),
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Apisix gateway route name",
 				Optional:            true,
@@ -184,22 +163,34 @@ func (r *RouteResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	}
 }
 
+func (r *RouteResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		pluginsJsonConflictValidator{},
+	}
+}
+
 func (r *RouteResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*api.ApisixClient)
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *api.ApisixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Client
+	r.clientSecretSource = providerData.ClientSecretSource
+}
+
+// ModifyPlan reports an openid_connect plugin without a client secret source before apply.
+func (r *RouteResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(validateClientSecretSource(ctx, req.Plan, r.client != nil, r.clientSecretSource)...)
 }
 
 func buildInfraTimeout(input *Timeout) *model.Timeout {
@@ -229,47 +220,12 @@ func buildTimeout(timeout *model.Timeout) *Timeout {
 	}
 }
 
-func fetchClientSecret(clientId string) (string, error) {
-	return "client_secret", nil
-}
-
-func buildPlugins(plugins *model.Plugins) *Plugins {
-	if (plugins == nil) || (plugins.OpenIdConnectPlugin == nil) {
-		return &Plugins{}
-	}
-	return &Plugins{
-		OpenIdConnectPlugin: &OpenIdConnectPlugin{
-			ClientId:       plugins.OpenIdConnectPlugin.ClientId,
-			Discovery:      plugins.OpenIdConnectPlugin.Discovery,
-			RequiredScopes: plugins.OpenIdConnectPlugin.RequiredScopes,
-		},
-	}
-}
-
-func buildInfraPlugins(plugins *Plugins) (*model.Plugins, error) {
-	if plugins == nil || plugins.OpenIdConnectPlugin == nil {
-		return nil, nil
+// stringValueOrNull keeps optional attributes null in state when apisix omits them.
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
 	}
-	secret, err := fetchClientSecret(plugins.OpenIdConnectPlugin.ClientId)
-	if err != nil {
-		return nil, err
-	}
-	return &model.Plugins{
-		OpenIdConnectPlugin: &model.OpenIdConnectPlugin{
-			ClientId:       plugins.OpenIdConnectPlugin.ClientId,
-			ClientSecret:   secret,
-			Discovery:      plugins.OpenIdConnectPlugin.Discovery,
-			RequiredScopes: plugins.OpenIdConnectPlugin.RequiredScopes,
-			// Just set to default value as they are not variable
-			BearerOnly:            true,
-			UseJwks:               true,
-			JwkExpiresIn:          600,
-			AudienceRequired:      true,
-			Audience:              "aud",
-			AudienceMatchClientId: true,
-			Realm:                 "silas-apisix-gateway",
-		},
-	}, nil
+	return types.StringValue(value)
 }
 
 func (r *RouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -281,7 +237,7 @@ func (r *RouteResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	plugins, err := buildInfraPlugins(data.Plugins)
+	plugins, err := buildInfraPlugins(data.Plugins, data.PluginsJson, r.clientSecretSource)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating plugins",
@@ -292,19 +248,21 @@ func (r *RouteResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	// Generate API request body from plan
 	route := &model.Route{
-		ID:         data.ID.ValueString(),
-		Uris:       data.Uris,
-		UpstreamId: data.UpstreamId.ValueString(),
-		Plugins:    plugins,
-		Name:       data.Name.ValueString(),
-		Desc:       data.Desc.ValueString(),
-		Hosts:      data.Hosts,
-		Methods:    data.Methods,
-		Priority:   int(data.Priority.ValueInt32()),
-		Vars:       data.Vars,
-		Labels:     data.Labels,
-		Timeout:    buildInfraTimeout(data.Timeout),
-		Status:     1,
+		ID:             data.ID.ValueString(),
+		Uris:           data.Uris,
+		UpstreamId:     data.UpstreamId.ValueString(),
+		ServiceId:      data.ServiceId.ValueString(),
+		PluginConfigId: data.PluginConfigId.ValueString(),
+		Plugins:        plugins,
+		Name:           data.Name.ValueString(),
+		Desc:           data.Desc.ValueString(),
+		Hosts:          data.Hosts,
+		Methods:        data.Methods,
+		Priority:       int(data.Priority.ValueInt32()),
+		Vars:           data.Vars,
+		Labels:         data.Labels,
+		Timeout:        buildInfraTimeout(data.Timeout),
+		Status:         1,
 	}
 
 	createdRoute, err := r.client.CreateRoute(route)
@@ -320,8 +278,11 @@ func (r *RouteResource) Create(ctx context.Context, req resource.CreateRequest,
 	// API response reflects the latest state of the route
 	data.ID = types.StringValue(createdRoute.ID)
 	data.Uris = createdRoute.Uris
-	data.UpstreamId = types.StringValue(createdRoute.UpstreamId)
-	data.Plugins = buildPlugins(createdRoute.Plugins)
+	data.UpstreamId = stringValueOrNull(createdRoute.UpstreamId)
+	data.ServiceId = stringValueOrNull(createdRoute.ServiceId)
+	data.PluginConfigId = stringValueOrNull(createdRoute.PluginConfigId)
+	data.Plugins = buildPlugins(createdRoute.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(createdRoute.Plugins, data.PluginsJson)
 	data.Name = types.StringValue(createdRoute.Name)
 	data.Desc = types.StringValue(createdRoute.Desc)
 	data.Hosts = createdRoute.Hosts
@@ -358,8 +319,11 @@ func (r *RouteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	data.ID = types.StringValue(route.ID)
 	data.Uris = route.Uris
-	data.UpstreamId = types.StringValue(route.UpstreamId)
-	data.Plugins = buildPlugins(route.Plugins)
+	data.UpstreamId = stringValueOrNull(route.UpstreamId)
+	data.ServiceId = stringValueOrNull(route.ServiceId)
+	data.PluginConfigId = stringValueOrNull(route.PluginConfigId)
+	data.Plugins = buildPlugins(route.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(route.Plugins, data.PluginsJson)
 	data.Name = types.StringValue(route.Name)
 	data.Desc = types.StringValue(route.Desc)
 	data.Hosts = route.Hosts
@@ -383,7 +347,7 @@ func (r *RouteResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	// Generate API request body from plan
-	plugins, err := buildInfraPlugins(data.Plugins)
+	plugins, err := buildInfraPlugins(data.Plugins, data.PluginsJson, r.clientSecretSource)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating plugins",
@@ -392,19 +356,21 @@ func (r *RouteResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 	route := &model.Route{
-		ID:         data.ID.ValueString(),
-		Uris:       data.Uris,
-		UpstreamId: data.UpstreamId.ValueString(),
-		Plugins:    plugins,
-		Name:       data.Name.ValueString(),
-		Desc:       data.Desc.ValueString(),
-		Hosts:      data.Hosts,
-		Methods:    data.Methods,
-		Priority:   int(data.Priority.ValueInt32()),
-		Vars:       data.Vars,
-		Labels:     data.Labels,
-		Timeout:    buildInfraTimeout(data.Timeout),
-		Status:     int(data.Status.ValueInt32()),
+		ID:             data.ID.ValueString(),
+		Uris:           data.Uris,
+		UpstreamId:     data.UpstreamId.ValueString(),
+		ServiceId:      data.ServiceId.ValueString(),
+		PluginConfigId: data.PluginConfigId.ValueString(),
+		Plugins:        plugins,
+		Name:           data.Name.ValueString(),
+		Desc:           data.Desc.ValueString(),
+		Hosts:          data.Hosts,
+		Methods:        data.Methods,
+		Priority:       int(data.Priority.ValueInt32()),
+		Vars:           data.Vars,
+		Labels:         data.Labels,
+		Timeout:        buildInfraTimeout(data.Timeout),
+		Status:         int(data.Status.ValueInt32()),
 	}
 
 	updatedRoute, err := r.client.UpdateRoute(route)
@@ -420,8 +386,11 @@ func (r *RouteResource) Update(ctx context.Context, req resource.UpdateRequest,
 	// API response reflects the latest state of the route
 	data.ID = types.StringValue(updatedRoute.ID)
 	data.Uris = updatedRoute.Uris
-	data.UpstreamId = types.StringValue(updatedRoute.UpstreamId)
-	data.Plugins = buildPlugins(updatedRoute.Plugins)
+	data.UpstreamId = stringValueOrNull(updatedRoute.UpstreamId)
+	data.ServiceId = stringValueOrNull(updatedRoute.ServiceId)
+	data.PluginConfigId = stringValueOrNull(updatedRoute.PluginConfigId)
+	data.Plugins = buildPlugins(updatedRoute.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(updatedRoute.Plugins, data.PluginsJson)
 	data.Name = types.StringValue(updatedRoute.Name)
 	data.Desc = types.StringValue(updatedRoute.Desc)
 	data.Hosts = updatedRoute.Hosts
@@ -54,6 +54,9 @@ resource "apisix_route" "ssf-java-sdk-springboot3-demo-dynLoggingLevel" {
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.discovery", "https://domain.authing.cn/oidc/.well-known/jwks.json"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.required_scopes.0", "admin"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.required_scopes.1", "book"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.bearer_only", "true"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.jwk_expires_in", "600"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.realm", "silas-apisix-gateway"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "name", "ssf-java-sdk-springboot3-demo-dynLoggingLevel"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "desc", "ssf-java-sdk-springboot3-demo dynLoggingLevel"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "methods.0", "GET"),
@@ -75,7 +78,28 @@ resource "apisix_route" "ssf-java-sdk-springboot3-demo-dynLoggingLevel" {
         client_id = "client-id"
         discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
         required_scopes = ["admin", "book", "stuff"]    
+        bearer_only = false
+        realm = "ssf-demo"
+        redirect_uri = "https://demo.example.com/callback"
+        session = {
+          secret = "0123456789abcdef"
+        }
        }
+      proxy_rewrite = {
+        regex_uri = ["^/api/v1/demo/(.*)", "/$1"]
+        headers = {
+          set = {
+            "X-Gateway" = "apisix"
+          }
+          remove = ["X-Debug"]
+        }
+      }
+      cors = {
+        allow_origins = "https://demo.example.com"
+        allow_methods = "GET,POST"
+        allow_headers = "Authorization,Content-Type"
+        allow_credential = true
+      }
     }
     name = "ssf-java-sdk-springboot3-demo-dynLoggingLevel"
     desc = "ssf-java-sdk-springboot3-demo dynLoggingLevel"
@@ -98,6 +122,17 @@ resource "apisix_route" "ssf-java-sdk-springboot3-demo-dynLoggingLevel" {
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.required_scopes.0", "admin"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.required_scopes.1", "book"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.required_scopes.2", "stuff"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.bearer_only", "false"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.realm", "ssf-demo"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.redirect_uri", "https://demo.example.com/callback"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.openid_connect.session.secret", "0123456789abcdef"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.proxy_rewrite.regex_uri.0", "^/api/v1/demo/(.*)"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.proxy_rewrite.headers.set.X-Gateway", "apisix"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.proxy_rewrite.headers.remove.0", "X-Debug"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.cors.allow_origins", "https://demo.example.com"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.cors.allow_methods", "GET,POST"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.cors.allow_headers", "Authorization,Content-Type"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "plugins.cors.max_age", "5"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "name", "ssf-java-sdk-springboot3-demo-dynLoggingLevel"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "desc", "ssf-java-sdk-springboot3-demo dynLoggingLevel"),
 					resource.TestCheckResourceAttr("apisix_route.ssf-java-sdk-springboot3-demo-dynLoggingLevel", "methods.0", "GET"),
@@ -111,3 +146,87 @@ resource "apisix_route" "ssf-java-sdk-springboot3-demo-dynLoggingLevel" {
 		},
 	})
 }
+
+func TestApisixRouteResourceAuthPlugins(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_route" "ssf-demo-auth" {
+    id = "ssf-demo-auth"
+    uris = ["/api/v1/demo/auth"]
+    upstream_id = "1"
+    name = "ssf-demo-auth"
+    desc = "ssf-demo auth plugins"
+    priority = 0
+    timeout = {
+      connect = 10
+      send = 10
+      read = 10
+    }
+    status = 1
+    plugins = {
+      key_auth = {
+        header = "X-Api-Key"
+      }
+      forward_auth = {
+        uri = "http://auth.example.com/verify"
+        request_headers = ["Authorization"]
+        upstream_headers = ["X-User-ID"]
+      }
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "name", "ssf-demo-auth"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.key_auth.header", "X-Api-Key"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.key_auth.query", "apikey"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.key_auth.hide_credentials", "false"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.forward_auth.uri", "http://auth.example.com/verify"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.forward_auth.request_method", "GET"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.forward_auth.request_headers.0", "Authorization"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.forward_auth.upstream_headers.0", "X-User-ID"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.forward_auth.timeout", "3000"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_route" "ssf-demo-auth" {
+    id = "ssf-demo-auth"
+    uris = ["/api/v1/demo/auth"]
+    upstream_id = "1"
+    name = "ssf-demo-auth"
+    desc = "ssf-demo auth plugins"
+    priority = 0
+    timeout = {
+      connect = 10
+      send = 10
+      read = 10
+    }
+    status = 1
+    plugins = {
+      jwt_auth = {
+        cookie = "token"
+        hide_credentials = true
+      }
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.jwt_auth.header", "authorization"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.jwt_auth.query", "jwt"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.jwt_auth.cookie", "token"),
+					resource.TestCheckResourceAttr("apisix_route.ssf-demo-auth", "plugins.jwt_auth.hide_credentials", "true"),
+					resource.TestCheckNoResourceAttr("apisix_route.ssf-demo-auth", "plugins.key_auth"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
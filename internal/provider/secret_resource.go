@@ -0,0 +1,366 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+	"strings"
+)
+
+const (
+	secretManagerVault = "vault"
+	secretManagerAws   = "aws"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SecretResource{}
+var _ resource.ResourceWithImportState = &SecretResource{}
+var _ resource.ResourceWithConfigValidators = &SecretResource{}
+
+func NewSecretResource() resource.Resource {
+	return &SecretResource{}
+}
+
+// SecretResource defines the resource implementation, plugins reference the secret with
+// `$secret://{manager}/{id}/{key}`.
+type SecretResource struct {
+	client *api.ApisixClient
+}
+
+// SecretResourceModel describes the resource data model.
+type SecretResourceModel struct {
+	ID    types.String `tfsdk:"id"`
+	Vault *VaultSecret `tfsdk:"vault"`
+	Aws   *AwsSecret   `tfsdk:"aws"`
+}
+
+type VaultSecret struct {
+	Uri    types.String `tfsdk:"uri"`
+	Prefix types.String `tfsdk:"prefix"`
+	Token  types.String `tfsdk:"token"`
+}
+
+type AwsSecret struct {
+	Region          types.String `tfsdk:"region"`
+	AccessKeyId     types.String `tfsdk:"access_key_id"`
+	SecretAccessKey types.String `tfsdk:"secret_access_key"`
+	SessionToken    types.String `tfsdk:"session_token"`
+	EndpointUrl     types.String `tfsdk:"endpoint_url"`
+}
+
+func (r *SecretResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret"
+}
+
+// requiresReplaceOnManagerChange replaces the secret when it moves to another secret manager,
+// the manager is part of the apisix secret ID.
+var requiresReplaceOnManagerChange = objectplanmodifier.RequiresReplaceIf(
+	func(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = req.StateValue.IsNull() != req.PlanValue.IsNull()
+	},
+	"Changing the secret manager requires replacing the secret",
+	"Changing the secret manager requires replacing the secret",
+)
+
+func (r *SecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "secret resource, a Vault or AWS secret manager referenced by `$secret://` in plugin configs, import it with `manager/id`",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway secret ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vault": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"uri": schema.StringAttribute{
+						MarkdownDescription: "URI of the vault server, like https://vault.example.com:8200",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpUrlRegex, "must be a http(s) URL"),
+						},
+					},
+					"prefix": schema.StringAttribute{
+						MarkdownDescription: "Key prefix of the secrets, like kv/apisix",
+						Required:            true,
+					},
+					"token": schema.StringAttribute{
+						MarkdownDescription: "Token apisix authenticates to vault with",
+						Required:            true,
+						Sensitive:           true,
+					},
+				},
+				MarkdownDescription: "Apisix gateway vault secret manager, conflicts with aws",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					requiresReplaceOnManagerChange,
+				},
+			},
+			"aws": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"region": schema.StringAttribute{
+						MarkdownDescription: "AWS region of the secrets manager, default us-east-1",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("us-east-1"),
+					},
+					"access_key_id": schema.StringAttribute{
+						MarkdownDescription: "AWS access key ID",
+						Required:            true,
+						Sensitive:           true,
+					},
+					"secret_access_key": schema.StringAttribute{
+						MarkdownDescription: "AWS secret access key",
+						Required:            true,
+						Sensitive:           true,
+					},
+					"session_token": schema.StringAttribute{
+						MarkdownDescription: "AWS session token of temporary credentials",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"endpoint_url": schema.StringAttribute{
+						MarkdownDescription: "Endpoint of the secrets manager, for VPC endpoints or compatible services",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpUrlRegex, "must be a http(s) URL"),
+						},
+					},
+				},
+				MarkdownDescription: "Apisix gateway AWS secrets manager, conflicts with vault",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					requiresReplaceOnManagerChange,
+				},
+			},
+		},
+	}
+}
+
+func (r *SecretResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("vault"),
+			path.MatchRoot("aws"),
+		),
+	}
+}
+
+func (r *SecretResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+// secretManager returns the apisix secret manager of the configured block.
+func secretManager(data *SecretResourceModel) string {
+	if data.Aws != nil {
+		return secretManagerAws
+	}
+	return secretManagerVault
+}
+
+func buildInfraSecret(data *SecretResourceModel) *model.Secret {
+	secret := &model.Secret{
+		ID:      data.ID.ValueString(),
+		Manager: secretManager(data),
+	}
+	if data.Vault != nil {
+		secret.Vault = &model.VaultSecret{
+			Uri:    data.Vault.Uri.ValueString(),
+			Prefix: data.Vault.Prefix.ValueString(),
+			Token:  data.Vault.Token.ValueString(),
+		}
+	}
+	if data.Aws != nil {
+		secret.Aws = &model.AwsSecret{
+			Region:          data.Aws.Region.ValueString(),
+			AccessKeyId:     data.Aws.AccessKeyId.ValueString(),
+			SecretAccessKey: data.Aws.SecretAccessKey.ValueString(),
+			SessionToken:    data.Aws.SessionToken.ValueString(),
+			EndpointUrl:     data.Aws.EndpointUrl.ValueString(),
+		}
+	}
+	return secret
+}
+
+// fillSecretModel converts the secret returned by apisix, keeping the credentials of the prior
+// model when it is known. They are returned encrypted when apisix data encryption is enabled,
+// on import the prior block is empty and the returned credentials are used.
+func fillSecretModel(data *SecretResourceModel, secret *model.Secret) {
+	data.ID = types.StringValue(secret.ID)
+
+	var vault *VaultSecret
+	if secret.Vault != nil {
+		vault = &VaultSecret{
+			Uri:    types.StringValue(secret.Vault.Uri),
+			Prefix: types.StringValue(secret.Vault.Prefix),
+			Token:  types.StringValue(secret.Vault.Token),
+		}
+		if data.Vault != nil && !data.Vault.Token.IsNull() {
+			vault.Token = data.Vault.Token
+		}
+	}
+	data.Vault = vault
+
+	var aws *AwsSecret
+	if secret.Aws != nil {
+		aws = &AwsSecret{
+			Region:          types.StringValue(secret.Aws.Region),
+			AccessKeyId:     types.StringValue(secret.Aws.AccessKeyId),
+			SecretAccessKey: types.StringValue(secret.Aws.SecretAccessKey),
+			SessionToken:    stringValueOrNull(secret.Aws.SessionToken),
+			EndpointUrl:     stringValueOrNull(secret.Aws.EndpointUrl),
+		}
+		if data.Aws != nil && !data.Aws.AccessKeyId.IsNull() {
+			aws.AccessKeyId = data.Aws.AccessKeyId
+			aws.SecretAccessKey = data.Aws.SecretAccessKey
+			aws.SessionToken = data.Aws.SessionToken
+		}
+	}
+	data.Aws = aws
+}
+
+func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SecretResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createdSecret, err := r.client.CreateSecret(buildInfraSecret(&data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating secret",
+			"Could not create secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the secret
+	fillSecretModel(&data, createdSecret)
+
+	tflog.Trace(ctx, "created a resource "+secretManager(&data)+"/"+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SecretResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := r.client.GetSecretById(secretManager(&data), data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting secret",
+			"Could not get secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillSecretModel(&data, secret)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SecretResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updatedSecret, err := r.client.UpdateSecret(buildInfraSecret(&data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating secret",
+			"Could not update secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillSecretModel(&data, updatedSecret)
+
+	tflog.Trace(ctx, "updated a resource "+secretManager(&data)+"/"+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SecretResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteSecretById(secretManager(&data), data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting secret",
+			"Could not delete secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState sets an empty block of the manager so Read knows where to look the secret up.
+func (r *SecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 || parts[1] == "" || (parts[0] != secretManagerVault && parts[0] != secretManagerAws) {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: manager/id, manager is vault or aws. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	if parts[0] == secretManagerAws {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("aws"), &AwsSecret{})...)
+	} else {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vault"), &VaultSecret{})...)
+	}
+}
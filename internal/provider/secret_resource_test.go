@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixSecretResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_secret" "demo" {
+    id = "demo"
+    vault = {
+      uri = "http://172.18.21.240:8200"
+      prefix = "kv/apisix"
+      token = "root"
+    }
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_secret.demo", "id", "demo"),
+					resource.TestCheckResourceAttr("apisix_secret.demo", "vault.prefix", "kv/apisix"),
+					resource.TestCheckResourceAttr("apisix_secret.demo", "vault.token", "root"),
+					resource.TestCheckNoResourceAttr("apisix_secret.demo", "aws"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_secret.demo",
+				ImportState:       true,
+				ImportStateId:     "vault/demo",
+				ImportStateVerify: true,
+				// apisix returns the token encrypted when data encryption is enabled
+				ImportStateVerifyIgnore: []string{"vault.token"},
+			},
+			// Update and Read testing, moving to another manager replaces the secret
+			{
+				Config: providerConfig + `
+resource "apisix_secret" "demo" {
+    id = "demo"
+    aws = {
+      region = "ap-east-1"
+      access_key_id = "access-key-id"
+      secret_access_key = "secret-access-key"
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("apisix_secret.demo", "vault"),
+					resource.TestCheckResourceAttr("apisix_secret.demo", "aws.region", "ap-east-1"),
+					resource.TestCheckNoResourceAttr("apisix_secret.demo", "aws.endpoint_url"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,294 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ServiceResource{}
+var _ resource.ResourceWithImportState = &ServiceResource{}
+var _ resource.ResourceWithUpgradeState = &ServiceResource{}
+var _ resource.ResourceWithConfigValidators = &ServiceResource{}
+var _ resource.ResourceWithModifyPlan = &ServiceResource{}
+
+func NewServiceResource() resource.Resource {
+	return &ServiceResource{}
+}
+
+// ServiceResource defines the resource implementation.
+type ServiceResource struct {
+	client             *api.ApisixClient
+	clientSecretSource ClientSecretSource
+}
+
+// ServiceResourceModel describes the resource data model.
+type ServiceResourceModel struct {
+	ID              types.String                    `tfsdk:"id"`
+	Name            types.String                    `tfsdk:"name"`
+	Desc            types.String                    `tfsdk:"desc"`
+	Labels          map[string]string               `tfsdk:"labels"`
+	Hosts           []string                        `tfsdk:"hosts"`
+	UpstreamId      types.String                    `tfsdk:"upstream_id"`
+	Upstream        *InlineUpstream                 `tfsdk:"upstream"`
+	Plugins         *Plugins                        `tfsdk:"plugins"`
+	PluginsJson     map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+	EnableWebsocket types.Bool                      `tfsdk:"enable_websocket"`
+}
+
+func (r *ServiceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service"
+}
+
+func (r *ServiceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "service resource, shares plugins and upstream across routes",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway service ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway service name",
+				Optional:            true,
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway service desc",
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Apisix gateway service labels",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"hosts": schema.ListAttribute{
+				MarkdownDescription: "Apisix gateway service hosts",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"upstream_id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway service upstream ID, conflicts with upstream",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("upstream")),
+				},
+			},
+			"upstream":     inlineUpstreamSchema("Apisix gateway service inline upstream, conflicts with upstream_id"),
+			"plugins":      pluginsSchema("Apisix gateway service plugins"),
+			"plugins_json": pluginsJsonSchema("Apisix gateway service raw plugins"),
+			"enable_websocket": schema.BoolAttribute{
+				MarkdownDescription: "Apisix gateway service enable websocket",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+func (r *ServiceResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		pluginsJsonConflictValidator{},
+	}
+}
+
+func (r *ServiceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: upgradeUpstreamNodesState("upstream", "nodes"),
+	}
+}
+
+func (r *ServiceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.clientSecretSource = providerData.ClientSecretSource
+}
+
+// ModifyPlan reports an openid_connect plugin without a client secret source before apply.
+func (r *ServiceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(validateClientSecretSource(ctx, req.Plan, r.client != nil, r.clientSecretSource)...)
+}
+
+func buildInfraService(data *ServiceResourceModel, clientSecretSource ClientSecretSource) (*model.Service, error) {
+	plugins, err := buildInfraPlugins(data.Plugins, data.PluginsJson, clientSecretSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Service{
+		ID:              data.ID.ValueString(),
+		Name:            data.Name.ValueString(),
+		Desc:            data.Desc.ValueString(),
+		Labels:          data.Labels,
+		Hosts:           data.Hosts,
+		UpstreamId:      data.UpstreamId.ValueString(),
+		Upstream:        buildInfraInlineUpstream(data.Upstream),
+		Plugins:         plugins,
+		EnableWebsocket: data.EnableWebsocket.ValueBool(),
+	}, nil
+}
+
+func fillServiceModel(data *ServiceResourceModel, service *model.Service) {
+	data.ID = types.StringValue(service.ID)
+	data.Name = stringValueOrNull(service.Name)
+	data.Desc = stringValueOrNull(service.Desc)
+	data.Labels = service.Labels
+	data.Hosts = service.Hosts
+	data.UpstreamId = stringValueOrNull(service.UpstreamId)
+	data.Upstream = buildInlineUpstream(service.Upstream, data.Upstream)
+	data.Plugins = buildPlugins(service.Plugins, data.Plugins)
+	data.PluginsJson = buildPluginsJson(service.Plugins, data.PluginsJson)
+	data.EnableWebsocket = types.BoolValue(service.EnableWebsocket)
+}
+
+func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ServiceResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	service, err := buildInfraService(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	createdService, err := r.client.CreateService(service)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating service",
+			"Could not create service, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the service
+	fillServiceModel(&data, createdService)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ServiceResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	service, err := r.client.GetServiceById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting service",
+			"Could not get service, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillServiceModel(&data, service)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ServiceResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	service, err := buildInfraService(&data, r.clientSecretSource)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedService, err := r.client.UpdateService(service)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating service",
+			"Could not update service, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillServiceModel(&data, updatedService)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ServiceResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteServiceById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting service",
+			"Could not delete service, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *ServiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixServiceResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_service" "demo" {
+    id = "demo"
+    name = "demo"
+    desc = "Shared upstream and plugins of demo routes"
+    labels = {
+      team = "ssf"
+    }
+    hosts = ["demo.example.com"]
+    upstream_id = "1"
+    plugins = {
+      openid_connect = {
+        client_id = "client-id"
+        discovery = "https://domain.authing.cn/oidc/.well-known/jwks.json"
+        required_scopes = ["admin"]
+       }
+    }
+ }
+
+resource "apisix_route" "demo" {
+    id = "demo"
+    uris = ["/api/v1/demo/*"]
+    service_id = apisix_service.demo.id
+    name = "demo"
+    desc = "Demo route of the demo service"
+    priority = 0
+    timeout = {
+      connect = 10
+      send = 10
+      read = 10
+    }
+    status = 1
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_service.demo", "id", "demo"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "name", "demo"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "labels.team", "ssf"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "hosts.0", "demo.example.com"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "upstream_id", "1"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "plugins.openid_connect.client_id", "client-id"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "enable_websocket", "false"),
+					resource.TestCheckResourceAttr("apisix_route.demo", "service_id", "demo"),
+					resource.TestCheckNoResourceAttr("apisix_route.demo", "upstream_id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_service.demo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_service" "demo" {
+    id = "demo"
+    name = "demo"
+    desc = "Shared upstream and plugins of demo routes"
+    upstream = {
+      type = "roundrobin"
+      nodes = [{
+        host = "127.0.0.1"
+        port = 80
+        weight = 1
+      }]
+    }
+    plugins = {
+      limit_req = {
+        rate = 10
+        burst = 5
+        key = "remote_addr"
+      }
+      limit_count = {
+        count = 1000
+        time_window = 60
+        key = "remote_addr"
+        policy = "redis"
+        redis = {
+          host = "172.18.21.241"
+          password = "redis-password"
+        }
+      }
+    }
+    enable_websocket = true
+ }
+
+resource "apisix_route" "demo" {
+    id = "demo"
+    uris = ["/api/v1/demo/*"]
+    service_id = apisix_service.demo.id
+    name = "demo"
+    desc = "Demo route of the demo service"
+    priority = 0
+    timeout = {
+      connect = 10
+      send = 10
+      read = 10
+    }
+    status = 1
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_service.demo", "id", "demo"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "upstream.type", "roundrobin"),
+					resource.TestCheckTypeSetElemNestedAttrs("apisix_service.demo", "upstream.nodes.*", map[string]string{"host": "127.0.0.1", "port": "80"}),
+					resource.TestCheckNoResourceAttr("apisix_service.demo", "upstream_id"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "enable_websocket", "true"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "plugins.limit_req.rejected_code", "503"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "plugins.limit_count.redis.port", "6379"),
+					resource.TestCheckResourceAttr("apisix_service.demo", "plugins.limit_count.redis.password", "redis-password"),
+					resource.TestCheckNoResourceAttr("apisix_service.demo", "plugins.openid_connect"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
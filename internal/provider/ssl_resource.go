@@ -0,0 +1,431 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+const (
+	SSLTypeServer = "server"
+	SSLTypeClient = "client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SSLResource{}
+var _ resource.ResourceWithImportState = &SSLResource{}
+var _ resource.ResourceWithValidateConfig = &SSLResource{}
+var _ resource.ResourceWithModifyPlan = &SSLResource{}
+
+func NewSSLResource() resource.Resource {
+	return &SSLResource{}
+}
+
+// SSLResource defines the resource implementation.
+type SSLResource struct {
+	client *api.ApisixClient
+}
+
+// SSLResourceModel describes the resource data model.
+type SSLResourceModel struct {
+	ID            types.String      `tfsdk:"id"`
+	Cert          types.String      `tfsdk:"cert"`
+	Key           types.String      `tfsdk:"key"`
+	Snis          []string          `tfsdk:"snis"`
+	Client        *SSLClient        `tfsdk:"client"`
+	Type          types.String      `tfsdk:"type"`
+	Labels        map[string]string `tfsdk:"labels"`
+	Status        types.Int32       `tfsdk:"status"`
+	ValidityStart types.Int64       `tfsdk:"validity_start"`
+	ValidityEnd   types.Int64       `tfsdk:"validity_end"`
+}
+
+type SSLClient struct {
+	CA    types.String `tfsdk:"ca"`
+	Depth types.Int32  `tfsdk:"depth"`
+}
+
+func (r *SSLResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssl"
+}
+
+func (r *SSLResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "ssl resource, certificate and private key served for the given SNIs",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway ssl ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded certificate, may be followed by its intermediate certificates",
+				Required:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key of the certificate",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"snis": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "SNIs served by the certificate, each must be covered by the certificate SANs",
+				Optional:            true,
+			},
+			"client": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"ca": schema.StringAttribute{
+						MarkdownDescription: "PEM encoded CA certificate used to verify client certificates",
+						Required:            true,
+					},
+					"depth": schema.Int32Attribute{
+						MarkdownDescription: "Max certificate chain depth of client certificates",
+						Optional:            true,
+						Validators: []validator.Int32{
+							int32validator.AtLeast(0),
+						},
+					},
+				},
+				MarkdownDescription: "mTLS settings of clients connecting to apisix",
+				Optional:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway ssl type, server or client, default server",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(SSLTypeServer),
+				Validators: []validator.String{
+					stringvalidator.OneOf(SSLTypeServer, SSLTypeClient),
+				},
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Apisix gateway ssl labels",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"status": schema.Int32Attribute{
+				MarkdownDescription: "Apisix gateway ssl status, 1 enabled and 0 disabled, default 1",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1),
+				Validators: []validator.Int32{
+					int32validator.OneOf(0, 1),
+				},
+			},
+			"validity_start": schema.Int64Attribute{
+				MarkdownDescription: "Unix timestamp the certificate is valid from",
+				Computed:            true,
+			},
+			"validity_end": schema.Int64Attribute{
+				MarkdownDescription: "Unix timestamp the certificate expires at",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SSLResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+// parseCertificate parses the leaf certificate, the first PEM block of certPEM.
+func parseCertificate(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded CERTIFICATE block found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// validateCertificate parses certPEM and checks keyPEM is its private key, reporting errors against certPath and keyPath.
+// The certificate is nil when it is unknown, null or invalid.
+func validateCertificate(certPath path.Path, keyPath path.Path, certPEM types.String, keyPEM types.String, diags *diag.Diagnostics) *x509.Certificate {
+	if certPEM.IsUnknown() || certPEM.IsNull() {
+		return nil
+	}
+
+	cert, err := parseCertificate(certPEM.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			certPath,
+			"Invalid certificate",
+			fmt.Sprintf("Could not parse %s, unexpected error: %s", certPath, err),
+		)
+		return nil
+	}
+
+	if !keyPEM.IsUnknown() && !keyPEM.IsNull() {
+		if _, err := tls.X509KeyPair([]byte(certPEM.ValueString()), []byte(keyPEM.ValueString())); err != nil {
+			diags.AddAttributeError(
+				keyPath,
+				"Invalid private key",
+				fmt.Sprintf("The %s is not a valid PEM encoded private key of %s: %s", keyPath, certPath, err),
+			)
+		}
+	}
+	return cert
+}
+
+// uncoveredSnis returns the SNIs not matched by the certificate SANs, wildcard SNIs must be present as is.
+func uncoveredSnis(cert *x509.Certificate, snis []string) []string {
+	uncovered := make([]string, 0)
+	for _, sni := range snis {
+		if cert.VerifyHostname(sni) != nil {
+			uncovered = append(uncovered, sni)
+		}
+	}
+	return uncovered
+}
+
+func (r *SSLResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var certPEM, keyPEM, clientCA, sslType types.String
+	var snis types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cert"), &certPEM)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("key"), &keyPEM)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("client").AtName("ca"), &clientCA)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &sslType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("snis"), &snis)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values from other resources are unknown until apply, they are checked by apisix instead
+	if !clientCA.IsUnknown() && !clientCA.IsNull() {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(clientCA.ValueString())) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("client").AtName("ca"),
+				"Invalid client CA certificate",
+				"The client ca does not contain any valid PEM encoded certificate.",
+			)
+		}
+	}
+
+	cert := validateCertificate(path.Root("cert"), path.Root("key"), certPEM, keyPEM, &resp.Diagnostics)
+	if cert == nil {
+		return
+	}
+
+	if sslType.ValueString() == SSLTypeClient || snis.IsUnknown() || snis.IsNull() {
+		return
+	}
+	knownSnis := make([]string, 0, len(snis.Elements()))
+	for _, element := range snis.Elements() {
+		if sni, ok := element.(types.String); ok && !sni.IsUnknown() && !sni.IsNull() {
+			knownSnis = append(knownSnis, sni.ValueString())
+		}
+	}
+	if uncovered := uncoveredSnis(cert, knownSnis); len(uncovered) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("snis"),
+			"SNIs not covered by certificate",
+			fmt.Sprintf("The certificate SANs %v do not cover the SNIs %v.", cert.DNSNames, uncovered),
+		)
+	}
+}
+
+// ModifyPlan fills the validity of the certificate into the plan, so it is known before apply.
+func (r *SSLResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var cert types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cert"), &cert)...)
+	if resp.Diagnostics.HasError() || cert.IsUnknown() {
+		return
+	}
+
+	parsed, err := parseCertificate(cert.ValueString())
+	if err != nil {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("validity_start"), parsed.NotBefore.Unix())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("validity_end"), parsed.NotAfter.Unix())...)
+}
+
+func buildInfraSSL(data *SSLResourceModel) *model.SSL {
+	ssl := &model.SSL{
+		ID:     data.ID.ValueString(),
+		Cert:   data.Cert.ValueString(),
+		Key:    data.Key.ValueString(),
+		Snis:   data.Snis,
+		Type:   data.Type.ValueString(),
+		Labels: data.Labels,
+		Status: int(data.Status.ValueInt32()),
+	}
+	if data.Client != nil {
+		ssl.Client = &model.SSLClient{
+			CA:    data.Client.CA.ValueString(),
+			Depth: int(data.Client.Depth.ValueInt32()),
+		}
+	}
+	return ssl
+}
+
+// fillSSLModel sets the state from the apisix response, the key is kept as configured
+// as apisix returns it encrypted.
+func fillSSLModel(data *SSLResourceModel, ssl *model.SSL) {
+	data.ID = types.StringValue(ssl.ID)
+	data.Cert = types.StringValue(ssl.Cert)
+	data.Snis = ssl.Snis
+	data.Type = types.StringValue(ssl.Type)
+	if ssl.Type == "" {
+		data.Type = types.StringValue(SSLTypeServer)
+	}
+	data.Labels = ssl.Labels
+	data.Status = types.Int32Value(int32(ssl.Status))
+	data.Client = nil
+	if ssl.Client != nil {
+		data.Client = &SSLClient{
+			CA:    types.StringValue(ssl.Client.CA),
+			Depth: types.Int32Null(),
+		}
+		if ssl.Client.Depth != 0 {
+			data.Client.Depth = types.Int32Value(int32(ssl.Client.Depth))
+		}
+	}
+
+	data.ValidityStart = types.Int64Null()
+	data.ValidityEnd = types.Int64Null()
+	if cert, err := parseCertificate(ssl.Cert); err == nil {
+		data.ValidityStart = types.Int64Value(cert.NotBefore.Unix())
+		data.ValidityEnd = types.Int64Value(cert.NotAfter.Unix())
+	}
+}
+
+func (r *SSLResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SSLResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createdSSL, err := r.client.CreateSSL(buildInfraSSL(&data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating ssl",
+			"Could not create ssl, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the ssl
+	fillSSLModel(&data, createdSSL)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SSLResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SSLResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ssl, err := r.client.GetSSLById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting ssl",
+			"Could not get ssl, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillSSLModel(&data, ssl)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SSLResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SSLResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updatedSSL, err := r.client.UpdateSSL(buildInfraSSL(&data))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating ssl",
+			"Could not update ssl, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillSSLModel(&data, updatedSSL)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SSLResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SSLResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteSSLById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting ssl",
+			"Could not delete ssl, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *SSLResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
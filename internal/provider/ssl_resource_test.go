@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// generateCertificate returns a self signed PEM encoded certificate and private key for dnsNames.
+func generateCertificate(t *testing.T, notAfter time.Time, dnsNames ...string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestUncoveredSnis(t *testing.T) {
+	certPEM, _ := generateCertificate(t, time.Now().Add(time.Hour), "example.com", "*.api.example.com")
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uncovered := uncoveredSnis(cert, []string{"example.com", "v1.api.example.com", "*.api.example.com", "other.com", "a.b.api.example.com"})
+	if fmt.Sprint(uncovered) != "[other.com a.b.api.example.com]" {
+		t.Errorf("unexpected uncovered snis: %v", uncovered)
+	}
+
+	if _, err := parseCertificate("not a certificate"); err == nil {
+		t.Errorf("expected error parsing invalid certificate")
+	}
+}
+
+func TestApisixSSLResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	notAfter := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+	cert, key := generateCertificate(t, notAfter, "example.com", "*.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "apisix_ssl" "example" {
+    id = "example"
+    cert = <<EOT
+%sEOT
+    key = <<EOT
+%sEOT
+    snis = ["example.com", "www.example.com"]
+ }
+`, cert, key),
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_ssl.example", "id", "example"),
+					resource.TestCheckResourceAttr("apisix_ssl.example", "snis.0", "example.com"),
+					resource.TestCheckResourceAttr("apisix_ssl.example", "type", "server"),
+					resource.TestCheckResourceAttr("apisix_ssl.example", "status", "1"),
+					resource.TestCheckResourceAttr("apisix_ssl.example", "validity_end", strconv.FormatInt(notAfter.Unix(), 10)),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "apisix_ssl" "example" {
+    id = "example"
+    cert = <<EOT
+%sEOT
+    key = <<EOT
+%sEOT
+    snis = ["example.com"]
+    labels = {
+      team = "ssf"
+    }
+    status = 0
+ }
+`, cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_ssl.example", "snis.#", "1"),
+					resource.TestCheckResourceAttr("apisix_ssl.example", "labels.team", "ssf"),
+					resource.TestCheckResourceAttr("apisix_ssl.example", "status", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestValidateCertificate(t *testing.T) {
+	cert, key := generateCertificate(t, time.Now().Add(time.Hour), "client.example.com")
+	_, otherKey := generateCertificate(t, time.Now().Add(time.Hour), "other.example.com")
+
+	cases := map[string]struct {
+		cert    types.String
+		key     types.String
+		parsed  bool
+		wantErr string
+	}{
+		"pair":          {types.StringValue(cert), types.StringValue(key), true, ""},
+		"unknown key":   {types.StringValue(cert), types.StringUnknown(), true, ""},
+		"unknown cert":  {types.StringUnknown(), types.StringValue(key), false, ""},
+		"not PEM":       {types.StringValue("not a certificate"), types.StringValue(key), false, "Invalid certificate"},
+		"mismatched":    {types.StringValue(cert), types.StringValue(otherKey), true, "Invalid private key"},
+		"key not a PEM": {types.StringValue(cert), types.StringValue("not a key"), true, "Invalid private key"},
+	}
+
+	for name, c := range cases {
+		var diags diag.Diagnostics
+		parsed := validateCertificate(path.Root("tls").AtName("client_cert"), path.Root("tls").AtName("client_key"), c.cert, c.key, &diags)
+		if (parsed != nil) != c.parsed {
+			t.Errorf("%s: expected parsed=%v, got %v", name, c.parsed, parsed)
+		}
+		if c.wantErr == "" && diags.HasError() {
+			t.Errorf("%s: unexpected diagnostics %v", name, diags)
+		}
+		if c.wantErr != "" && (!diags.HasError() || diags.Errors()[0].Summary() != c.wantErr) {
+			t.Errorf("%s: expected %q, got diagnostics %v", name, c.wantErr, diags)
+		}
+	}
+}
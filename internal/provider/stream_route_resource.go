@@ -0,0 +1,405 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+var sniRegex = regexp.MustCompile(`^\*?[0-9a-zA-Z\-._]+$`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StreamRouteResource{}
+var _ resource.ResourceWithImportState = &StreamRouteResource{}
+var _ resource.ResourceWithUpgradeState = &StreamRouteResource{}
+var _ resource.ResourceWithConfigValidators = &StreamRouteResource{}
+
+func NewStreamRouteResource() resource.Resource {
+	return &StreamRouteResource{}
+}
+
+// StreamRouteResource defines the resource implementation.
+type StreamRouteResource struct {
+	client *api.ApisixClient
+}
+
+// StreamRouteResourceModel describes the resource data model.
+type StreamRouteResourceModel struct {
+	ID          types.String                    `tfsdk:"id"`
+	Desc        types.String                    `tfsdk:"desc"`
+	ServerAddr  types.String                    `tfsdk:"server_addr"`
+	ServerPort  types.Int32                     `tfsdk:"server_port"`
+	RemoteAddr  types.String                    `tfsdk:"remote_addr"`
+	Sni         types.String                    `tfsdk:"sni"`
+	UpstreamId  types.String                    `tfsdk:"upstream_id"`
+	Upstream    *InlineUpstream                 `tfsdk:"upstream"`
+	Plugins     *StreamPlugins                  `tfsdk:"plugins"`
+	PluginsJson map[string]jsontypes.Normalized `tfsdk:"plugins_json"`
+}
+
+// StreamPlugins are the L4 plugins of the stream subsystem.
+type StreamPlugins struct {
+	IpRestriction *IpRestrictionPlugin `tfsdk:"ip_restriction"`
+	LimitConn     *LimitConnPlugin     `tfsdk:"limit_conn"`
+}
+
+type IpRestrictionPlugin struct {
+	Whitelist []string     `tfsdk:"whitelist"`
+	Blacklist []string     `tfsdk:"blacklist"`
+	Message   types.String `tfsdk:"message"`
+}
+
+func (r *StreamRouteResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stream_route"
+}
+
+func (r *StreamRouteResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "stream route resource, L4 TCP/UDP proxy of the stream subsystem",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway stream route ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"desc": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway stream route desc",
+				Optional:            true,
+			},
+			"server_addr": schema.StringAttribute{
+				MarkdownDescription: "Address of apisix the client connects to",
+				Optional:            true,
+				Validators: []validator.String{
+					isIPAddress(),
+				},
+			},
+			"server_port": schema.Int32Attribute{
+				MarkdownDescription: "Port of apisix the client connects to",
+				Optional:            true,
+				Validators: []validator.Int32{
+					int32validator.Between(1, 65535),
+				},
+			},
+			"remote_addr": schema.StringAttribute{
+				MarkdownDescription: "Address or CIDR of the client",
+				Optional:            true,
+				Validators: []validator.String{
+					isIPAddressOrCIDR(),
+				},
+			},
+			"sni": schema.StringAttribute{
+				MarkdownDescription: "Server name indication of TLS connections",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(sniRegex, "must be a host name, optionally with a leading wildcard"),
+				},
+			},
+			"upstream_id": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway stream route upstream ID, conflicts with upstream",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("upstream")),
+				},
+			},
+			"upstream": inlineUpstreamSchema("Apisix gateway stream route inline upstream, conflicts with upstream_id"),
+			"plugins": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"ip_restriction": schema.SingleNestedAttribute{
+						Attributes: map[string]schema.Attribute{
+							"whitelist": schema.ListAttribute{
+								ElementType:         types.StringType,
+								MarkdownDescription: "Addresses or CIDRs allowed, conflicts with blacklist",
+								Optional:            true,
+								Validators: []validator.List{
+									listvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("blacklist")),
+									listvalidator.ValueStringsAre(isIPAddressOrCIDR()),
+								},
+							},
+							"blacklist": schema.ListAttribute{
+								ElementType:         types.StringType,
+								MarkdownDescription: "Addresses or CIDRs denied, conflicts with whitelist",
+								Optional:            true,
+								Validators: []validator.List{
+									listvalidator.ValueStringsAre(isIPAddressOrCIDR()),
+								},
+							},
+							"message": schema.StringAttribute{
+								MarkdownDescription: "Message returned when access is denied",
+								Optional:            true,
+							},
+						},
+						MarkdownDescription: "ip-restriction plugin",
+						Optional:            true,
+					},
+					"limit_conn": limitConnPluginSchema(),
+				},
+				MarkdownDescription: "Apisix gateway stream route plugins",
+				Optional:            true,
+			},
+			"plugins_json": pluginsJsonSchema("Apisix gateway stream route raw plugins of the stream subsystem"),
+		},
+	}
+}
+
+func (r *StreamRouteResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		pluginsJsonConflictValidator{},
+	}
+}
+
+func (r *StreamRouteResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: upgradeUpstreamNodesState("upstream", "nodes"),
+	}
+}
+
+func (r *StreamRouteResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+// buildInfraStreamPlugins merges the typed plugins with the raw plugins of plugins_json.
+func buildInfraStreamPlugins(plugins *StreamPlugins, pluginsJson map[string]jsontypes.Normalized) (*model.StreamPlugins, error) {
+	extra, err := buildInfraPluginsJson(pluginsJson)
+	if err != nil {
+		return nil, err
+	}
+	if plugins == nil {
+		if extra == nil {
+			return nil, nil
+		}
+		return &model.StreamPlugins{Extra: extra}, nil
+	}
+
+	infraPlugins := &model.StreamPlugins{
+		LimitConn: buildInfraLimitConnPlugin(plugins.LimitConn),
+		Extra:     extra,
+	}
+	if plugins.IpRestriction != nil {
+		infraPlugins.IpRestriction = &model.IpRestrictionPlugin{
+			Whitelist: plugins.IpRestriction.Whitelist,
+			Blacklist: plugins.IpRestriction.Blacklist,
+			Message:   plugins.IpRestriction.Message.ValueString(),
+		}
+	}
+	return infraPlugins, nil
+}
+
+// buildStreamPlugins converts the plugins returned by apisix, the ip-restriction message apisix fills in
+// a default for is taken from prior to avoid diffs against an unset config.
+func buildStreamPlugins(plugins *model.StreamPlugins, prior *StreamPlugins) *StreamPlugins {
+	if plugins == nil {
+		return nil
+	}
+	// Keep plugins null when none of them is configured, like when only plugins_json is used
+	configured := prior != nil
+
+	built := &StreamPlugins{
+		LimitConn: buildLimitConnPlugin(plugins.LimitConn),
+	}
+	if plugins.IpRestriction != nil {
+		built.IpRestriction = &IpRestrictionPlugin{
+			Whitelist: plugins.IpRestriction.Whitelist,
+			Blacklist: plugins.IpRestriction.Blacklist,
+			Message:   stringValueOrNull(plugins.IpRestriction.Message),
+		}
+		if prior != nil && prior.IpRestriction != nil {
+			built.IpRestriction.Message = prior.IpRestriction.Message
+		}
+	}
+	if !configured && *built == (StreamPlugins{}) {
+		return nil
+	}
+	return built
+}
+
+func buildInfraStreamRoute(data *StreamRouteResourceModel) (*model.StreamRoute, error) {
+	plugins, err := buildInfraStreamPlugins(data.Plugins, data.PluginsJson)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StreamRoute{
+		ID:         data.ID.ValueString(),
+		Desc:       data.Desc.ValueString(),
+		ServerAddr: data.ServerAddr.ValueString(),
+		ServerPort: int(data.ServerPort.ValueInt32()),
+		RemoteAddr: data.RemoteAddr.ValueString(),
+		Sni:        data.Sni.ValueString(),
+		UpstreamId: data.UpstreamId.ValueString(),
+		Upstream:   buildInfraInlineUpstream(data.Upstream),
+		Plugins:    plugins,
+	}, nil
+}
+
+func fillStreamRouteModel(data *StreamRouteResourceModel, streamRoute *model.StreamRoute) {
+	data.ID = types.StringValue(streamRoute.ID)
+	data.Desc = stringValueOrNull(streamRoute.Desc)
+	data.ServerAddr = stringValueOrNull(streamRoute.ServerAddr)
+	data.ServerPort = types.Int32Null()
+	if streamRoute.ServerPort != 0 {
+		data.ServerPort = types.Int32Value(int32(streamRoute.ServerPort))
+	}
+	data.RemoteAddr = stringValueOrNull(streamRoute.RemoteAddr)
+	data.Sni = stringValueOrNull(streamRoute.Sni)
+	data.UpstreamId = stringValueOrNull(streamRoute.UpstreamId)
+	data.Upstream = buildInlineUpstream(streamRoute.Upstream, data.Upstream)
+	data.Plugins = buildStreamPlugins(streamRoute.Plugins, data.Plugins)
+	var extra map[string]any
+	if streamRoute.Plugins != nil {
+		extra = streamRoute.Plugins.Extra
+	}
+	data.PluginsJson = buildExtraPluginsJson(extra, data.PluginsJson)
+}
+
+func (r *StreamRouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data StreamRouteResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	streamRoute, err := buildInfraStreamRoute(&data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	createdStreamRoute, err := r.client.CreateStreamRoute(streamRoute)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating stream route",
+			"Could not create stream route, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Update the plan with create API response and save it to .tfstate file
+	// API response reflects the latest state of the stream route
+	fillStreamRouteModel(&data, createdStreamRoute)
+
+	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *StreamRouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data StreamRouteResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	streamRoute, err := r.client.GetStreamRouteById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting stream route",
+			"Could not get stream route, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillStreamRouteModel(&data, streamRoute)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StreamRouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data StreamRouteResourceModel
+	// Read Terraform plan data into the model
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate API request body from plan
+	streamRoute, err := buildInfraStreamRoute(&data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating plugins",
+			"Could not create plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedStreamRoute, err := r.client.UpdateStreamRoute(streamRoute)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating stream route",
+			"Could not update stream route, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fillStreamRouteModel(&data, updatedStreamRoute)
+
+	tflog.Trace(ctx, "updated a resource "+data.ID.ValueString())
+	// Save data into Terraform state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *StreamRouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data StreamRouteResourceModel
+	// Read Terraform prior state data into the model
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteStreamRouteById(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting stream route",
+			"Could not delete stream route, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *StreamRouteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+	"silas.com/ssf-terraform/apisix-client/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestApisixStreamRouteResource(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_stream_route" "mysql" {
+    id = "mysql"
+    desc = "Proxy mysql connections"
+    server_port = 9100
+    remote_addr = "10.0.0.0/8"
+    upstream = {
+      type = "roundrobin"
+      nodes = [{
+        host = "172.18.21.10"
+        port = 3306
+        weight = 1
+      }]
+    }
+    plugins = {
+      ip_restriction = {
+        whitelist = ["10.0.0.0/8", "127.0.0.1"]
+      }
+    }
+ }
+`,
+
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "id", "mysql"),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "server_port", "9100"),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "remote_addr", "10.0.0.0/8"),
+					resource.TestCheckTypeSetElemNestedAttrs("apisix_stream_route.mysql", "upstream.nodes.*", map[string]string{"host": "172.18.21.10", "port": "3306"}),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "plugins.ip_restriction.whitelist.1", "127.0.0.1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "apisix_stream_route.mysql",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_stream_route" "mysql" {
+    id = "mysql"
+    desc = "Proxy mysql connections"
+    server_port = 9100
+    sni = "*.mysql.example.com"
+    upstream_id = "1"
+    plugins = {
+      limit_conn = {
+        conn = 100
+        burst = 50
+        default_conn_delay = 0.1
+        key = "remote_addr"
+      }
+    }
+    plugins_json = {
+      "syslog" = jsonencode({ host = "172.18.21.240", port = 514 })
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("apisix_stream_route.mysql", "remote_addr"),
+					resource.TestCheckNoResourceAttr("apisix_stream_route.mysql", "upstream"),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "sni", "*.mysql.example.com"),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "plugins.limit_conn.conn", "100"),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "plugins.limit_conn.key_type", "var"),
+					resource.TestCheckResourceAttr("apisix_stream_route.mysql", "plugins.limit_conn.only_use_default_delay", "false"),
+					resource.TestCheckResourceAttrSet("apisix_stream_route.mysql", "plugins_json.syslog"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestIPAddressValidator(t *testing.T) {
+	cases := []struct {
+		value     string
+		validator ipAddressValidator
+		valid     bool
+	}{
+		{"127.0.0.1", ipAddressValidator{}, true},
+		{"::1", ipAddressValidator{}, true},
+		{"10.0.0.0/8", ipAddressValidator{}, false},
+		{"10.0.0.0/8", ipAddressValidator{allowCIDR: true}, true},
+		{"2001:db8::/32", ipAddressValidator{allowCIDR: true}, true},
+		{"localhost", ipAddressValidator{allowCIDR: true}, false},
+		{"10.0.0.256", ipAddressValidator{allowCIDR: true}, false},
+		{"127.0.0.1", ipAddressValidator{ipv4Only: true}, true},
+		{"::1", ipAddressValidator{ipv4Only: true}, false},
+		{"2001:db8::/32", ipAddressValidator{allowCIDR: true, ipv4Only: true}, false},
+	}
+
+	for _, c := range cases {
+		req := validator.StringRequest{Path: path.Root("addr"), ConfigValue: types.StringValue(c.value)}
+		resp := &validator.StringResponse{}
+		c.validator.ValidateString(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%q (%+v): expected valid=%v, got diagnostics %v", c.value, c.validator, c.valid, resp.Diagnostics)
+		}
+	}
+}
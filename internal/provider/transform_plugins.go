@@ -0,0 +1,422 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+var headerLineRegex = regexp.MustCompile(`^[^:\s]+:\s*\S.*$`)
+
+// ProxyRewritePlugin rewrites the request before it is passed to the upstream.
+type ProxyRewritePlugin struct {
+	Uri                     types.String         `tfsdk:"uri"`
+	Method                  types.String         `tfsdk:"method"`
+	RegexUri                []string             `tfsdk:"regex_uri"`
+	Host                    types.String         `tfsdk:"host"`
+	Headers                 *ProxyRewriteHeaders `tfsdk:"headers"`
+	UseRealRequestUriUnsafe types.Bool           `tfsdk:"use_real_request_uri_unsafe"`
+}
+
+type ProxyRewriteHeaders struct {
+	Set    map[string]string `tfsdk:"set"`
+	Add    map[string]string `tfsdk:"add"`
+	Remove []string          `tfsdk:"remove"`
+}
+
+// ResponseRewritePlugin rewrites the response before it is returned to the client.
+type ResponseRewritePlugin struct {
+	StatusCode types.Int64             `tfsdk:"status_code"`
+	Body       types.String            `tfsdk:"body"`
+	BodyBase64 types.Bool              `tfsdk:"body_base64"`
+	Headers    *ResponseRewriteHeaders `tfsdk:"headers"`
+}
+
+type ResponseRewriteHeaders struct {
+	Set    map[string]string `tfsdk:"set"`
+	Add    []string          `tfsdk:"add"`
+	Remove []string          `tfsdk:"remove"`
+}
+
+type RedirectPlugin struct {
+	Uri               types.String `tfsdk:"uri"`
+	HttpToHttps       types.Bool   `tfsdk:"http_to_https"`
+	RegexUri          []string     `tfsdk:"regex_uri"`
+	RetCode           types.Int64  `tfsdk:"ret_code"`
+	EncodeUri         types.Bool   `tfsdk:"encode_uri"`
+	AppendQueryString types.Bool   `tfsdk:"append_query_string"`
+}
+
+type CorsPlugin struct {
+	AllowOrigins        types.String `tfsdk:"allow_origins"`
+	AllowMethods        types.String `tfsdk:"allow_methods"`
+	AllowHeaders        types.String `tfsdk:"allow_headers"`
+	ExposeHeaders       types.String `tfsdk:"expose_headers"`
+	MaxAge              types.Int64  `tfsdk:"max_age"`
+	AllowCredential     types.Bool   `tfsdk:"allow_credential"`
+	AllowOriginsByRegex []string     `tfsdk:"allow_origins_by_regex"`
+}
+
+func regexUriAttribute(description string) schema.ListAttribute {
+	return schema.ListAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: description + ", pairs of a regex and its replacement like [\"^/iresty/(.*)\", \"/$1\"], the first matching pair is used",
+		Optional:            true,
+		Validators: []validator.List{
+			regexUriValidator{},
+		},
+	}
+}
+
+func proxyRewritePluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"uri": schema.StringAttribute{
+				MarkdownDescription: "New upstream URI, supports variables like $uri. Takes precedence over regex_uri",
+				Optional:            true,
+			},
+			"method": schema.StringAttribute{
+				MarkdownDescription: "New upstream request method",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("GET", "POST", "PUT", "HEAD", "DELETE", "OPTIONS", "MKCOL", "COPY", "MOVE", "PROPFIND", "LOCK", "UNLOCK", "PATCH", "TRACE"),
+				},
+			},
+			"regex_uri": regexUriAttribute("Rewrite of the upstream URI"),
+			"host": schema.StringAttribute{
+				MarkdownDescription: "New upstream Host header",
+				Optional:            true,
+			},
+			"headers": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"set": schema.MapAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Headers to set, overriding the ones of the request",
+						Optional:            true,
+					},
+					"add": schema.MapAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Headers to append",
+						Optional:            true,
+					},
+					"remove": schema.ListAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Headers to remove",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Upstream request headers to change, applied in the order add, remove, set",
+				Optional:            true,
+			},
+			"use_real_request_uri_unsafe": schema.BoolAttribute{
+				MarkdownDescription: "Pass the raw request URI through without normalization, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		MarkdownDescription: "proxy-rewrite plugin, rewrites the upstream request",
+		Optional:            true,
+	}
+}
+
+func responseRewritePluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"status_code": schema.Int64Attribute{
+				MarkdownDescription: "New response status code",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(200, 598),
+				},
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "New response body, Content-Length is reset",
+				Optional:            true,
+			},
+			"body_base64": schema.BoolAttribute{
+				MarkdownDescription: "Decode body from base64 before returning it, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"headers": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"set": schema.MapAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Headers to set, overriding the ones of the response",
+						Optional:            true,
+					},
+					"add": schema.ListAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Headers to append, formatted as name: value",
+						Optional:            true,
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(stringvalidator.RegexMatches(headerLineRegex, "must be formatted as name: value")),
+						},
+					},
+					"remove": schema.ListAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Headers to remove",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Response headers to change, applied in the order add, remove, set",
+				Optional:            true,
+			},
+		},
+		MarkdownDescription: "response-rewrite plugin, rewrites the response",
+		Optional:            true,
+	}
+}
+
+func redirectPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"uri": schema.StringAttribute{
+				MarkdownDescription: "URI to redirect to, supports variables like $uri",
+				Optional:            true,
+			},
+			"http_to_https": schema.BoolAttribute{
+				MarkdownDescription: "Redirect http requests to the same URI with https",
+				Optional:            true,
+			},
+			"regex_uri": regexUriAttribute("Rewrite of the request URI to redirect to"),
+			"ret_code": schema.Int64Attribute{
+				MarkdownDescription: "Status code of the redirect, default 302",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(302),
+				Validators: []validator.Int64{
+					int64validator.Between(200, 599),
+				},
+			},
+			"encode_uri": schema.BoolAttribute{
+				MarkdownDescription: "Encode the Location header as RFC3986, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"append_query_string": schema.BoolAttribute{
+				MarkdownDescription: "Append the query string of the request to the Location header, default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		MarkdownDescription: "redirect plugin, exactly one of uri, http_to_https and regex_uri must be set",
+		Optional:            true,
+		Validators: []validator.Object{
+			redirectTargetValidator{},
+		},
+	}
+}
+
+func corsPluginSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"allow_origins": schema.StringAttribute{
+				MarkdownDescription: "Comma separated origins allowed like https://example.com:8081, * allows any. Default *",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("*"),
+			},
+			"allow_methods": schema.StringAttribute{
+				MarkdownDescription: "Comma separated methods allowed like GET,POST, * allows any. Default *",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("*"),
+			},
+			"allow_headers": schema.StringAttribute{
+				MarkdownDescription: "Comma separated request headers allowed, * allows any. Default *",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("*"),
+			},
+			"expose_headers": schema.StringAttribute{
+				MarkdownDescription: "Comma separated response headers exposed to the client",
+				Optional:            true,
+			},
+			"max_age": schema.Int64Attribute{
+				MarkdownDescription: "Seconds the preflight result is cached, -1 disables caching. Default 5",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(5),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(-1),
+				},
+			},
+			"allow_credential": schema.BoolAttribute{
+				MarkdownDescription: "Allow requests with credentials, the other allow_ attributes must not be * then. Default false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"allow_origins_by_regex": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Regexes of origins allowed, like ^https://.*\\.example\\.com$",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(regexValidator{}),
+				},
+			},
+		},
+		MarkdownDescription: "cors plugin, handles cross origin resource sharing",
+		Optional:            true,
+		Validators: []validator.Object{
+			corsCredentialValidator{},
+		},
+	}
+}
+
+func buildProxyRewritePlugin(plugin *model.ProxyRewritePlugin) *ProxyRewritePlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &ProxyRewritePlugin{
+		Uri:                     stringValueOrNull(plugin.Uri),
+		Method:                  stringValueOrNull(plugin.Method),
+		RegexUri:                plugin.RegexUri,
+		Host:                    stringValueOrNull(plugin.Host),
+		UseRealRequestUriUnsafe: types.BoolValue(plugin.UseRealRequestUriUnsafe),
+	}
+	if plugin.Headers != nil {
+		built.Headers = &ProxyRewriteHeaders{
+			Set:    plugin.Headers.Set,
+			Add:    plugin.Headers.Add,
+			Remove: plugin.Headers.Remove,
+		}
+	}
+	return built
+}
+
+func buildInfraProxyRewritePlugin(plugin *ProxyRewritePlugin) *model.ProxyRewritePlugin {
+	if plugin == nil {
+		return nil
+	}
+	infraPlugin := &model.ProxyRewritePlugin{
+		Uri:                     plugin.Uri.ValueString(),
+		Method:                  plugin.Method.ValueString(),
+		RegexUri:                plugin.RegexUri,
+		Host:                    plugin.Host.ValueString(),
+		UseRealRequestUriUnsafe: plugin.UseRealRequestUriUnsafe.ValueBool(),
+	}
+	if plugin.Headers != nil {
+		infraPlugin.Headers = &model.ProxyRewriteHeaders{
+			Set:    plugin.Headers.Set,
+			Add:    plugin.Headers.Add,
+			Remove: plugin.Headers.Remove,
+		}
+	}
+	return infraPlugin
+}
+
+func buildResponseRewritePlugin(plugin *model.ResponseRewritePlugin) *ResponseRewritePlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &ResponseRewritePlugin{
+		StatusCode: int64ValueOrNull(plugin.StatusCode),
+		Body:       stringValueOrNull(plugin.Body),
+		BodyBase64: types.BoolValue(plugin.BodyBase64),
+	}
+	if plugin.Headers != nil {
+		built.Headers = &ResponseRewriteHeaders{
+			Set:    plugin.Headers.Set,
+			Add:    plugin.Headers.Add,
+			Remove: plugin.Headers.Remove,
+		}
+	}
+	return built
+}
+
+func buildInfraResponseRewritePlugin(plugin *ResponseRewritePlugin) *model.ResponseRewritePlugin {
+	if plugin == nil {
+		return nil
+	}
+	infraPlugin := &model.ResponseRewritePlugin{
+		StatusCode: int(plugin.StatusCode.ValueInt64()),
+		Body:       plugin.Body.ValueString(),
+		BodyBase64: plugin.BodyBase64.ValueBool(),
+	}
+	if plugin.Headers != nil {
+		infraPlugin.Headers = &model.ResponseRewriteHeaders{
+			Set:    plugin.Headers.Set,
+			Add:    plugin.Headers.Add,
+			Remove: plugin.Headers.Remove,
+		}
+	}
+	return infraPlugin
+}
+
+// buildRedirectPlugin converts the redirect returned by apisix, http_to_https = false is kept
+// from prior as apisix does not tell it apart from an unset one.
+func buildRedirectPlugin(plugin *model.RedirectPlugin, prior *RedirectPlugin) *RedirectPlugin {
+	if plugin == nil {
+		return nil
+	}
+	built := &RedirectPlugin{
+		Uri:               stringValueOrNull(plugin.Uri),
+		HttpToHttps:       types.BoolNull(),
+		RegexUri:          plugin.RegexUri,
+		RetCode:           types.Int64Value(int64(plugin.RetCode)),
+		EncodeUri:         types.BoolValue(plugin.EncodeUri),
+		AppendQueryString: types.BoolValue(plugin.AppendQueryString),
+	}
+	if plugin.HttpToHttps || (prior != nil && !prior.HttpToHttps.IsNull()) {
+		built.HttpToHttps = types.BoolValue(plugin.HttpToHttps)
+	}
+	return built
+}
+
+func buildInfraRedirectPlugin(plugin *RedirectPlugin) *model.RedirectPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.RedirectPlugin{
+		Uri:               plugin.Uri.ValueString(),
+		HttpToHttps:       plugin.HttpToHttps.ValueBool(),
+		RegexUri:          plugin.RegexUri,
+		RetCode:           int(plugin.RetCode.ValueInt64()),
+		EncodeUri:         plugin.EncodeUri.ValueBool(),
+		AppendQueryString: plugin.AppendQueryString.ValueBool(),
+	}
+}
+
+func buildCorsPlugin(plugin *model.CorsPlugin) *CorsPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &CorsPlugin{
+		AllowOrigins:        types.StringValue(plugin.AllowOrigins),
+		AllowMethods:        types.StringValue(plugin.AllowMethods),
+		AllowHeaders:        types.StringValue(plugin.AllowHeaders),
+		ExposeHeaders:       stringValueOrNull(plugin.ExposeHeaders),
+		MaxAge:              types.Int64Value(int64(plugin.MaxAge)),
+		AllowCredential:     types.BoolValue(plugin.AllowCredential),
+		AllowOriginsByRegex: plugin.AllowOriginsByRegex,
+	}
+}
+
+func buildInfraCorsPlugin(plugin *CorsPlugin) *model.CorsPlugin {
+	if plugin == nil {
+		return nil
+	}
+	return &model.CorsPlugin{
+		AllowOrigins:        plugin.AllowOrigins.ValueString(),
+		AllowMethods:        plugin.AllowMethods.ValueString(),
+		AllowHeaders:        plugin.AllowHeaders.ValueString(),
+		ExposeHeaders:       plugin.ExposeHeaders.ValueString(),
+		MaxAge:              int(plugin.MaxAge.ValueInt64()),
+		AllowCredential:     plugin.AllowCredential.ValueBool(),
+		AllowOriginsByRegex: plugin.AllowOriginsByRegex,
+	}
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckRegex(t *testing.T) {
+	cases := map[string]bool{
+		`^/iresty/(.*)/(.*)`:  true,
+		`^/api/(?<name>\w+)$`: true,
+		// Valid PCRE unsupported by RE2
+		`^/(?!internal)(.*)`: true,
+		`^/(a+)\1$`:          true,
+		`^/a++$`:             true,
+		`^/(.*`:              false,
+		`^/(.*))`:            false,
+		`^/[a-z`:             false,
+		`^/[z-a]`:            false,
+		`*/api`:              false,
+		`^/api\`:             false,
+	}
+
+	for pattern, valid := range cases {
+		if err := checkRegex(pattern); (err == nil) != valid {
+			t.Errorf("%s: expected valid=%v, got %v", pattern, valid, err)
+		}
+	}
+}
+
+func TestRegexUriValidator(t *testing.T) {
+	cases := map[string]struct {
+		value []string
+		valid bool
+	}{
+		"pair":         {[]string{"^/iresty/(.*)", "/$1"}, true},
+		"two pairs":    {[]string{"^/v1/(.*)", "/$1", "^/v2/(.*)", "/api/$1"}, true},
+		"odd":          {[]string{"^/iresty/(.*)", "/$1", "^/v2/(.*)"}, false},
+		"empty":        {[]string{}, false},
+		"malformed":    {[]string{"^/iresty/(.*", "/$1"}, false},
+		"replacement":  {[]string{"^/iresty/(.*)", "/(.*"}, true},
+		"second regex": {[]string{"^/v1/(.*)", "/$1", "^/v2/[a-", "/$1"}, false},
+	}
+
+	for name, c := range cases {
+		value, _ := types.ListValueFrom(context.Background(), types.StringType, c.value)
+		req := validator.ListRequest{Path: path.Root("plugins").AtName("proxy_rewrite").AtName("regex_uri"), ConfigValue: value}
+		resp := &validator.ListResponse{}
+		regexUriValidator{}.ValidateList(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", name, c.valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestRedirectTargetValidator(t *testing.T) {
+	attributeTypes := map[string]attr.Type{
+		"uri":           types.StringType,
+		"http_to_https": types.BoolType,
+		"regex_uri":     types.ListType{ElemType: types.StringType},
+		"ret_code":      types.Int64Type,
+	}
+	regexUri, _ := types.ListValueFrom(context.Background(), types.StringType, []string{"^/iresty/(.*)", "/$1"})
+	noRegexUri := types.ListNull(types.StringType)
+
+	cases := map[string]struct {
+		uri         types.String
+		httpToHttps types.Bool
+		regexUri    types.List
+		valid       bool
+	}{
+		"uri":                    {types.StringValue("/new"), types.BoolNull(), noRegexUri, true},
+		"http to https":          {types.StringNull(), types.BoolValue(true), noRegexUri, true},
+		"regex uri":              {types.StringNull(), types.BoolNull(), regexUri, true},
+		"uri and false":          {types.StringValue("/new"), types.BoolValue(false), noRegexUri, true},
+		"unknown uri":            {types.StringUnknown(), types.BoolValue(true), noRegexUri, true},
+		"none":                   {types.StringNull(), types.BoolNull(), noRegexUri, false},
+		"only false":             {types.StringNull(), types.BoolValue(false), noRegexUri, false},
+		"uri and http to https":  {types.StringValue("/new"), types.BoolValue(true), noRegexUri, false},
+		"uri and regex uri":      {types.StringValue("/new"), types.BoolNull(), regexUri, false},
+		"all of them":            {types.StringValue("/new"), types.BoolValue(true), regexUri, false},
+		"http to https and rest": {types.StringNull(), types.BoolValue(true), regexUri, false},
+	}
+
+	for name, c := range cases {
+		value := types.ObjectValueMust(attributeTypes, map[string]attr.Value{
+			"uri":           c.uri,
+			"http_to_https": c.httpToHttps,
+			"regex_uri":     c.regexUri,
+			"ret_code":      types.Int64Value(302),
+		})
+		req := validator.ObjectRequest{Path: path.Root("plugins").AtName("redirect"), ConfigValue: value}
+		resp := &validator.ObjectResponse{}
+		redirectTargetValidator{}.ValidateObject(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", name, c.valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestCorsCredentialValidator(t *testing.T) {
+	attributeTypes := map[string]attr.Type{
+		"allow_origins":          types.StringType,
+		"allow_methods":          types.StringType,
+		"allow_headers":          types.StringType,
+		"expose_headers":         types.StringType,
+		"max_age":                types.Int64Type,
+		"allow_credential":       types.BoolType,
+		"allow_origins_by_regex": types.ListType{ElemType: types.StringType},
+	}
+	origins := types.StringValue("https://demo.example.com")
+	methods := types.StringValue("GET,POST")
+	headers := types.StringValue("Authorization,Content-Type")
+
+	cases := map[string]struct {
+		allowOrigins    types.String
+		allowMethods    types.String
+		allowHeaders    types.String
+		exposeHeaders   types.String
+		allowCredential types.Bool
+		valid           bool
+	}{
+		"explicit":                 {origins, methods, headers, types.StringValue("X-Request-Id"), types.BoolValue(true), true},
+		"no expose headers":        {origins, methods, headers, types.StringNull(), types.BoolValue(true), true},
+		"wildcards no credential":  {types.StringValue("*"), types.StringNull(), types.StringNull(), types.StringValue("*"), types.BoolValue(false), true},
+		"defaults no credential":   {types.StringNull(), types.StringNull(), types.StringNull(), types.StringNull(), types.BoolNull(), true},
+		"unknown origins":          {types.StringUnknown(), methods, headers, types.StringNull(), types.BoolValue(true), true},
+		"wildcard origins":         {types.StringValue("*"), methods, headers, types.StringNull(), types.BoolValue(true), false},
+		"wildcard in list":         {origins, types.StringValue("GET, *"), headers, types.StringNull(), types.BoolValue(true), false},
+		"wildcard expose headers":  {origins, methods, headers, types.StringValue("*"), types.BoolValue(true), false},
+		"default allow methods":    {origins, types.StringNull(), headers, types.StringNull(), types.BoolValue(true), false},
+		"default allow headers":    {origins, methods, types.StringNull(), types.StringNull(), types.BoolValue(true), false},
+		"default allow everything": {types.StringNull(), types.StringNull(), types.StringNull(), types.StringNull(), types.BoolValue(true), false},
+	}
+
+	for name, c := range cases {
+		value := types.ObjectValueMust(attributeTypes, map[string]attr.Value{
+			"allow_origins":          c.allowOrigins,
+			"allow_methods":          c.allowMethods,
+			"allow_headers":          c.allowHeaders,
+			"expose_headers":         c.exposeHeaders,
+			"max_age":                types.Int64Value(5),
+			"allow_credential":       c.allowCredential,
+			"allow_origins_by_regex": types.ListNull(types.StringType),
+		})
+		req := validator.ObjectRequest{Path: path.Root("plugins").AtName("cors"), ConfigValue: value}
+		resp := &validator.ObjectResponse{}
+		corsCredentialValidator{}.ValidateObject(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", name, c.valid, resp.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"silas.com/ssf-terraform/apisix-client/model"
+)
+
+// UpstreamChecks are the health checks of an upstream.
+type UpstreamChecks struct {
+	Active  *UpstreamActiveCheck  `tfsdk:"active"`
+	Passive *UpstreamPassiveCheck `tfsdk:"passive"`
+}
+
+type UpstreamActiveCheck struct {
+	Type                   types.String             `tfsdk:"type"`
+	Timeout                types.Float64            `tfsdk:"timeout"`
+	Concurrency            types.Int64              `tfsdk:"concurrency"`
+	Host                   types.String             `tfsdk:"host"`
+	Port                   types.Int64              `tfsdk:"port"`
+	HttpPath               types.String             `tfsdk:"http_path"`
+	HttpsVerifyCertificate types.Bool               `tfsdk:"https_verify_certificate"`
+	ReqHeaders             []string                 `tfsdk:"req_headers"`
+	Healthy                *UpstreamActiveHealthy   `tfsdk:"healthy"`
+	Unhealthy              *UpstreamActiveUnhealthy `tfsdk:"unhealthy"`
+}
+
+type UpstreamActiveHealthy struct {
+	Interval     types.Int64 `tfsdk:"interval"`
+	HttpStatuses []int64     `tfsdk:"http_statuses"`
+	Successes    types.Int64 `tfsdk:"successes"`
+}
+
+type UpstreamActiveUnhealthy struct {
+	Interval     types.Int64 `tfsdk:"interval"`
+	HttpStatuses []int64     `tfsdk:"http_statuses"`
+	HttpFailures types.Int64 `tfsdk:"http_failures"`
+	TcpFailures  types.Int64 `tfsdk:"tcp_failures"`
+	Timeouts     types.Int64 `tfsdk:"timeouts"`
+}
+
+type UpstreamPassiveCheck struct {
+	Type      types.String              `tfsdk:"type"`
+	Healthy   *UpstreamPassiveHealthy   `tfsdk:"healthy"`
+	Unhealthy *UpstreamPassiveUnhealthy `tfsdk:"unhealthy"`
+}
+
+type UpstreamPassiveHealthy struct {
+	HttpStatuses []int64     `tfsdk:"http_statuses"`
+	Successes    types.Int64 `tfsdk:"successes"`
+}
+
+type UpstreamPassiveUnhealthy struct {
+	HttpStatuses []int64     `tfsdk:"http_statuses"`
+	HttpFailures types.Int64 `tfsdk:"http_failures"`
+	TcpFailures  types.Int64 `tfsdk:"tcp_failures"`
+	Timeouts     types.Int64 `tfsdk:"timeouts"`
+}
+
+func checkTypeAttribute(checkTypes ...string) schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "Type of the check, default http",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString("http"),
+		Validators: []validator.String{
+			stringvalidator.OneOf(checkTypes...),
+		},
+	}
+}
+
+func httpStatusesAttribute(description string) schema.ListAttribute {
+	return schema.ListAttribute{
+		ElementType:         types.Int64Type,
+		MarkdownDescription: description,
+		Optional:            true,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+			listvalidator.UniqueValues(),
+			listvalidator.ValueInt64sAre(int64validator.Between(200, 599)),
+		},
+	}
+}
+
+// countAttribute is a number of checks between minimum and 254 that marks a node healthy or unhealthy.
+func countAttribute(description string, minimum int64, defaultValue int64) schema.Int64Attribute {
+	return schema.Int64Attribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		Default:             int64default.StaticInt64(defaultValue),
+		Validators: []validator.Int64{
+			int64validator.Between(minimum, 254),
+		},
+	}
+}
+
+func intervalAttribute(description string) schema.Int64Attribute {
+	return schema.Int64Attribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		Default:             int64default.StaticInt64(1),
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+		},
+	}
+}
+
+func upstreamChecksSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"active": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"type": checkTypeAttribute("http", "https", "tcp"),
+					"timeout": schema.Float64Attribute{
+						MarkdownDescription: "Timeout in seconds of a check, default 1",
+						Optional:            true,
+						Computed:            true,
+						Default:             float64default.StaticFloat64(1),
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0),
+						},
+					},
+					"concurrency": schema.Int64Attribute{
+						MarkdownDescription: "Nodes checked at the same time, default 10",
+						Optional:            true,
+						Computed:            true,
+						Default:             int64default.StaticInt64(10),
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"host": schema.StringAttribute{
+						MarkdownDescription: "Host header of http(s) checks",
+						Optional:            true,
+					},
+					"port": schema.Int64Attribute{
+						MarkdownDescription: "Port checked instead of the port of the node",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.Between(1, 65535),
+						},
+					},
+					"http_path": schema.StringAttribute{
+						MarkdownDescription: "Path of http(s) checks, default /",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("/"),
+					},
+					"https_verify_certificate": schema.BoolAttribute{
+						MarkdownDescription: "Verify the certificate of the node in https checks, default true",
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(true),
+					},
+					"req_headers": schema.ListAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Extra headers of http(s) checks, formatted as name: value",
+						Optional:            true,
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(stringvalidator.RegexMatches(headerLineRegex, "must be formatted as name: value")),
+						},
+					},
+					"healthy": schema.SingleNestedAttribute{
+						Attributes: map[string]schema.Attribute{
+							"interval":      intervalAttribute("Seconds between checks of healthy nodes, default 1"),
+							"http_statuses": httpStatusesAttribute("Status codes of healthy responses, default 200 and 302"),
+							"successes":     countAttribute("Successful checks that mark a node healthy, default 2", 1, 2),
+						},
+						MarkdownDescription: "When a node is healthy",
+						Optional:            true,
+					},
+					"unhealthy": schema.SingleNestedAttribute{
+						Attributes: map[string]schema.Attribute{
+							"interval":      intervalAttribute("Seconds between checks of unhealthy nodes, default 1"),
+							"http_statuses": httpStatusesAttribute("Status codes of unhealthy responses, default 429, 404, 500, 501, 502, 503, 504 and 505"),
+							"http_failures": countAttribute("Unhealthy responses that mark a node unhealthy, default 5", 1, 5),
+							"tcp_failures":  countAttribute("TCP failures that mark a node unhealthy, default 2", 1, 2),
+							"timeouts":      countAttribute("Timed out checks that mark a node unhealthy, default 3", 1, 3),
+						},
+						MarkdownDescription: "When a node is unhealthy",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Apisix gateway upstream active checks, nodes are probed periodically",
+				Required:            true,
+			},
+			"passive": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"type": checkTypeAttribute("http", "https", "tcp"),
+					"healthy": schema.SingleNestedAttribute{
+						Attributes: map[string]schema.Attribute{
+							"http_statuses": httpStatusesAttribute("Status codes of healthy responses, default 200, 201, 202, 203, 204, 205, 206, 207, 208, 226, 300, 301, 302, 303, 304, 305, 306, 307 and 308"),
+							"successes":     countAttribute("Successful requests that mark a node healthy, default 5", 0, 5),
+						},
+						MarkdownDescription: "When a node is healthy",
+						Optional:            true,
+					},
+					"unhealthy": schema.SingleNestedAttribute{
+						Attributes: map[string]schema.Attribute{
+							"http_statuses": httpStatusesAttribute("Status codes of unhealthy responses, default 429, 500 and 503"),
+							"http_failures": countAttribute("Unhealthy responses that mark a node unhealthy, default 5", 0, 5),
+							"tcp_failures":  countAttribute("TCP failures that mark a node unhealthy, default 2", 0, 2),
+							"timeouts":      countAttribute("Timed out requests that mark a node unhealthy, default 7", 0, 7),
+						},
+						MarkdownDescription: "When a node is unhealthy",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Apisix gateway upstream passive checks, nodes are judged by the proxied requests",
+				Optional:            true,
+			},
+		},
+		MarkdownDescription: "Apisix gateway upstream health checks, passive checks need active ones to bring unhealthy nodes back",
+		Optional:            true,
+	}
+}
+
+// httpStatuses converts the status codes returned by apisix, the defaults apisix fills in
+// are only taken when status codes were configured.
+func httpStatuses(statuses []int, prior []int64, configured bool) []int64 {
+	if configured && prior == nil {
+		return nil
+	}
+	if statuses == nil {
+		return nil
+	}
+	built := make([]int64, 0, len(statuses))
+	for _, status := range statuses {
+		built = append(built, int64(status))
+	}
+	return built
+}
+
+func buildInfraHttpStatuses(statuses []int64) []int {
+	if statuses == nil {
+		return nil
+	}
+	infraStatuses := make([]int, 0, len(statuses))
+	for _, status := range statuses {
+		infraStatuses = append(infraStatuses, int(status))
+	}
+	return infraStatuses
+}
+
+// buildUpstreamChecks converts the checks returned by apisix, nested blocks apisix fills in with
+// defaults are only taken when they were configured.
+func buildUpstreamChecks(checks *model.UpstreamChecks, prior *UpstreamChecks) *UpstreamChecks {
+	if checks == nil {
+		return nil
+	}
+	configured := prior != nil
+	if prior == nil {
+		prior = &UpstreamChecks{}
+	}
+
+	built := &UpstreamChecks{
+		Active: buildUpstreamActiveCheck(checks.Active, prior.Active),
+	}
+	if !configured || prior.Passive != nil {
+		built.Passive = buildUpstreamPassiveCheck(checks.Passive, prior.Passive)
+	}
+	return built
+}
+
+func buildUpstreamActiveCheck(check *model.UpstreamActiveCheck, prior *UpstreamActiveCheck) *UpstreamActiveCheck {
+	if check == nil {
+		return nil
+	}
+	configured := prior != nil
+	if prior == nil {
+		prior = &UpstreamActiveCheck{}
+	}
+
+	built := &UpstreamActiveCheck{
+		Type:                   types.StringValue(check.Type),
+		Timeout:                types.Float64Value(check.Timeout),
+		Concurrency:            types.Int64Value(int64(check.Concurrency)),
+		Host:                   stringValueOrNull(check.Host),
+		Port:                   int64ValueOrNull(check.Port),
+		HttpPath:               types.StringValue(check.HttpPath),
+		HttpsVerifyCertificate: types.BoolValue(check.HttpsVerifyCertificate),
+		ReqHeaders:             check.ReqHeaders,
+	}
+	if check.Healthy != nil && (!configured || prior.Healthy != nil) {
+		priorHealthy := prior.Healthy
+		if priorHealthy == nil {
+			priorHealthy = &UpstreamActiveHealthy{}
+		}
+		built.Healthy = &UpstreamActiveHealthy{
+			Interval:     types.Int64Value(int64(check.Healthy.Interval)),
+			HttpStatuses: httpStatuses(check.Healthy.HttpStatuses, priorHealthy.HttpStatuses, configured),
+			Successes:    types.Int64Value(int64(check.Healthy.Successes)),
+		}
+	}
+	if check.Unhealthy != nil && (!configured || prior.Unhealthy != nil) {
+		priorUnhealthy := prior.Unhealthy
+		if priorUnhealthy == nil {
+			priorUnhealthy = &UpstreamActiveUnhealthy{}
+		}
+		built.Unhealthy = &UpstreamActiveUnhealthy{
+			Interval:     types.Int64Value(int64(check.Unhealthy.Interval)),
+			HttpStatuses: httpStatuses(check.Unhealthy.HttpStatuses, priorUnhealthy.HttpStatuses, configured),
+			HttpFailures: types.Int64Value(int64(check.Unhealthy.HttpFailures)),
+			TcpFailures:  types.Int64Value(int64(check.Unhealthy.TcpFailures)),
+			Timeouts:     types.Int64Value(int64(check.Unhealthy.Timeouts)),
+		}
+	}
+	return built
+}
+
+func buildUpstreamPassiveCheck(check *model.UpstreamPassiveCheck, prior *UpstreamPassiveCheck) *UpstreamPassiveCheck {
+	if check == nil {
+		return nil
+	}
+	configured := prior != nil
+	if prior == nil {
+		prior = &UpstreamPassiveCheck{}
+	}
+
+	built := &UpstreamPassiveCheck{
+		Type: types.StringValue(check.Type),
+	}
+	if check.Healthy != nil && (!configured || prior.Healthy != nil) {
+		priorHealthy := prior.Healthy
+		if priorHealthy == nil {
+			priorHealthy = &UpstreamPassiveHealthy{}
+		}
+		built.Healthy = &UpstreamPassiveHealthy{
+			HttpStatuses: httpStatuses(check.Healthy.HttpStatuses, priorHealthy.HttpStatuses, configured),
+			Successes:    types.Int64Value(int64(check.Healthy.Successes)),
+		}
+	}
+	if check.Unhealthy != nil && (!configured || prior.Unhealthy != nil) {
+		priorUnhealthy := prior.Unhealthy
+		if priorUnhealthy == nil {
+			priorUnhealthy = &UpstreamPassiveUnhealthy{}
+		}
+		built.Unhealthy = &UpstreamPassiveUnhealthy{
+			HttpStatuses: httpStatuses(check.Unhealthy.HttpStatuses, priorUnhealthy.HttpStatuses, configured),
+			HttpFailures: types.Int64Value(int64(check.Unhealthy.HttpFailures)),
+			TcpFailures:  types.Int64Value(int64(check.Unhealthy.TcpFailures)),
+			Timeouts:     types.Int64Value(int64(check.Unhealthy.Timeouts)),
+		}
+	}
+	return built
+}
+
+func buildInfraUpstreamChecks(checks *UpstreamChecks) *model.UpstreamChecks {
+	if checks == nil {
+		return nil
+	}
+
+	infraChecks := &model.UpstreamChecks{}
+	if active := checks.Active; active != nil {
+		infraChecks.Active = &model.UpstreamActiveCheck{
+			Type:                   active.Type.ValueString(),
+			Timeout:                active.Timeout.ValueFloat64(),
+			Concurrency:            int(active.Concurrency.ValueInt64()),
+			Host:                   active.Host.ValueString(),
+			Port:                   int(active.Port.ValueInt64()),
+			HttpPath:               active.HttpPath.ValueString(),
+			HttpsVerifyCertificate: active.HttpsVerifyCertificate.ValueBool(),
+			ReqHeaders:             active.ReqHeaders,
+		}
+		if active.Healthy != nil {
+			infraChecks.Active.Healthy = &model.UpstreamHealthy{
+				Interval:     int(active.Healthy.Interval.ValueInt64()),
+				HttpStatuses: buildInfraHttpStatuses(active.Healthy.HttpStatuses),
+				Successes:    int(active.Healthy.Successes.ValueInt64()),
+			}
+		}
+		if active.Unhealthy != nil {
+			infraChecks.Active.Unhealthy = &model.UpstreamUnhealthy{
+				Interval:     int(active.Unhealthy.Interval.ValueInt64()),
+				HttpStatuses: buildInfraHttpStatuses(active.Unhealthy.HttpStatuses),
+				HttpFailures: int(active.Unhealthy.HttpFailures.ValueInt64()),
+				TcpFailures:  int(active.Unhealthy.TcpFailures.ValueInt64()),
+				Timeouts:     int(active.Unhealthy.Timeouts.ValueInt64()),
+			}
+		}
+	}
+	if passive := checks.Passive; passive != nil {
+		infraChecks.Passive = &model.UpstreamPassiveCheck{
+			Type: passive.Type.ValueString(),
+		}
+		if passive.Healthy != nil {
+			infraChecks.Passive.Healthy = &model.UpstreamHealthy{
+				HttpStatuses: buildInfraHttpStatuses(passive.Healthy.HttpStatuses),
+				Successes:    int(passive.Healthy.Successes.ValueInt64()),
+			}
+		}
+		if passive.Unhealthy != nil {
+			infraChecks.Passive.Unhealthy = &model.UpstreamUnhealthy{
+				HttpStatuses: buildInfraHttpStatuses(passive.Unhealthy.HttpStatuses),
+				HttpFailures: int(passive.Unhealthy.HttpFailures.ValueInt64()),
+				TcpFailures:  int(passive.Unhealthy.TcpFailures.ValueInt64()),
+				Timeouts:     int(passive.Unhealthy.Timeouts.ValueInt64()),
+			}
+		}
+	}
+	return infraChecks
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"reflect"
+	"silas.com/ssf-terraform/apisix-client/model"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestBuildUpstreamChecks(t *testing.T) {
+	// Checks as apisix returns them, with the defaults filled in
+	checks := &model.UpstreamChecks{
+		Active: &model.UpstreamActiveCheck{
+			Type:                   "http",
+			Timeout:                1,
+			Concurrency:            10,
+			HttpPath:               "/healthz",
+			HttpsVerifyCertificate: true,
+			Healthy:                &model.UpstreamHealthy{Interval: 2, HttpStatuses: []int{200, 302}, Successes: 2},
+			Unhealthy:              &model.UpstreamUnhealthy{Interval: 1, HttpStatuses: []int{500, 503}, HttpFailures: 5, TcpFailures: 2, Timeouts: 3},
+		},
+		Passive: &model.UpstreamPassiveCheck{
+			Type:      "http",
+			Healthy:   &model.UpstreamHealthy{HttpStatuses: []int{200}, Successes: 5},
+			Unhealthy: &model.UpstreamUnhealthy{HttpStatuses: []int{429, 500, 503}, HttpFailures: 3, TcpFailures: 2, Timeouts: 7},
+		},
+	}
+
+	prior := &UpstreamChecks{
+		Active: &UpstreamActiveCheck{
+			Healthy:   &UpstreamActiveHealthy{Interval: types.Int64Value(2)},
+			Unhealthy: &UpstreamActiveUnhealthy{HttpStatuses: []int64{500, 503}},
+		},
+	}
+	built := buildUpstreamChecks(checks, prior)
+	if built.Active.Healthy.HttpStatuses != nil {
+		t.Errorf("expected default healthy http_statuses to be left out, got %v", built.Active.Healthy.HttpStatuses)
+	}
+	if !reflect.DeepEqual(built.Active.Unhealthy.HttpStatuses, []int64{500, 503}) {
+		t.Errorf("expected unhealthy http_statuses [500 503], got %v", built.Active.Unhealthy.HttpStatuses)
+	}
+	if built.Active.Host != types.StringNull() || built.Active.Port != types.Int64Null() {
+		t.Errorf("expected unset host and port to be null, got %s and %s", built.Active.Host, built.Active.Port)
+	}
+	if built.Passive != nil {
+		t.Errorf("expected passive checks apisix filled in to be left out, got %v", built.Passive)
+	}
+
+	// Imported checks take everything apisix returns
+	imported := buildUpstreamChecks(checks, nil)
+	if !reflect.DeepEqual(imported.Active.Healthy.HttpStatuses, []int64{200, 302}) {
+		t.Errorf("expected imported healthy http_statuses [200 302], got %v", imported.Active.Healthy.HttpStatuses)
+	}
+	if imported.Passive == nil || imported.Passive.Unhealthy.Timeouts != types.Int64Value(7) {
+		t.Errorf("expected imported passive unhealthy timeouts 7, got %v", imported.Passive)
+	}
+
+	back := buildInfraUpstreamChecks(imported)
+	if !reflect.DeepEqual(back, checks) {
+		t.Errorf("expected checks to convert back unchanged, got %+v", back)
+	}
+}
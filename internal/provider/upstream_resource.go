@@ -2,26 +2,49 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net"
+	"regexp"
 	"silas.com/ssf-terraform/apisix-client/api"
 	"silas.com/ssf-terraform/apisix-client/model"
+	"slices"
+	"sort"
 	"strconv"
-	"strings"
 )
 
 const InvalidUpstreamHost = "invalid"
 const RewriteUpstreamHost = "rewrite"
+const DefaultPassHost = "pass"
+const DefaultUpstreamType = "roundrobin"
+
+// tlsSchemes are the upstream schemes apisix connects to the nodes with over TLS.
+var tlsSchemes = []string{"https", "grpcs", "tls"}
+
+var upstreamHostRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-.]*[a-zA-Z0-9])?$`)
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &UpstreamResource{}
 var _ resource.ResourceWithImportState = &UpstreamResource{}
+var _ resource.ResourceWithUpgradeState = &UpstreamResource{}
+var _ resource.ResourceWithConfigValidators = &UpstreamResource{}
+var _ resource.ResourceWithValidateConfig = &UpstreamResource{}
 
 func NewUpstreamResource() resource.Resource {
 	return &UpstreamResource{}
@@ -32,14 +55,169 @@ type UpstreamResource struct {
 }
 
 type UpstreamResourceModel struct {
-	ID           types.String `tfsdk:"id"`
-	Type         types.String `tfsdk:"type"`
-	Nodes        [][]string   `tfsdk:"nodes"`
-	Retries      types.Int32  `tfsdk:"retries"`
-	Name         types.String `tfsdk:"name"`
-	Desc         types.String `tfsdk:"desc"`
-	PassHost     types.String `tfsdk:"pass_host"`
-	UpstreamHost types.String `tfsdk:"upstream_host"`
+	ID            types.String           `tfsdk:"id"`
+	Type          types.String           `tfsdk:"type"`
+	Nodes         []UpstreamNode         `tfsdk:"nodes"`
+	Retries       types.Int32            `tfsdk:"retries"`
+	Name          types.String           `tfsdk:"name"`
+	Desc          types.String           `tfsdk:"desc"`
+	PassHost      types.String           `tfsdk:"pass_host"`
+	UpstreamHost  types.String           `tfsdk:"upstream_host"`
+	Checks        *UpstreamChecks        `tfsdk:"checks"`
+	ServiceName   types.String           `tfsdk:"service_name"`
+	DiscoveryType types.String           `tfsdk:"discovery_type"`
+	DiscoveryArgs *UpstreamDiscoveryArgs `tfsdk:"discovery_args"`
+	Scheme        types.String           `tfsdk:"scheme"`
+	TLS           *UpstreamTLS           `tfsdk:"tls"`
+}
+
+// UpstreamTLS is the TLS apisix uses towards the nodes, the client certificate is either inline or an apisix_ssl of type client.
+type UpstreamTLS struct {
+	ClientCert   types.String `tfsdk:"client_cert"`
+	ClientKey    types.String `tfsdk:"client_key"`
+	ClientCertId types.String `tfsdk:"client_cert_id"`
+	Verify       types.Bool   `tfsdk:"verify"`
+}
+
+// UpstreamDiscoveryArgs narrow down the discovered nodes, only nacos supports them.
+type UpstreamDiscoveryArgs struct {
+	NamespaceId types.String      `tfsdk:"namespace_id"`
+	GroupName   types.String      `tfsdk:"group_name"`
+	Metadata    map[string]string `tfsdk:"metadata"`
+}
+
+// InlineUpstream is an upstream embedded in another apisix object instead of referenced by upstream_id.
+type InlineUpstream struct {
+	Type         types.String   `tfsdk:"type"`
+	Nodes        []UpstreamNode `tfsdk:"nodes"`
+	Retries      types.Int32    `tfsdk:"retries"`
+	PassHost     types.String   `tfsdk:"pass_host"`
+	UpstreamHost types.String   `tfsdk:"upstream_host"`
+}
+
+type UpstreamNode struct {
+	Host     types.String      `tfsdk:"host"`
+	Port     types.Int64       `tfsdk:"port"`
+	Weight   types.Int64       `tfsdk:"weight"`
+	Priority types.Int64       `tfsdk:"priority"`
+	Metadata map[string]string `tfsdk:"metadata"`
+}
+
+func upstreamNodesSchema(required bool) schema.SetNestedAttribute {
+	return schema.SetNestedAttribute{
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"host": schema.StringAttribute{
+					MarkdownDescription: "Apisix gateway upstream node host, a domain name or an IPv4/IPv6 address without brackets",
+					Required:            true,
+					Validators: []validator.String{
+						stringvalidator.Any(
+							isIPAddress(),
+							stringvalidator.RegexMatches(upstreamHostRegex, "must be a domain name"),
+						),
+					},
+				},
+				"port": schema.Int64Attribute{
+					MarkdownDescription: "Apisix gateway upstream node port",
+					Required:            true,
+					Validators: []validator.Int64{
+						int64validator.Between(1, 65535),
+					},
+				},
+				"weight": schema.Int64Attribute{
+					MarkdownDescription: "Apisix gateway upstream node weight, 0 takes the node out of load balancing",
+					Required:            true,
+					Validators: []validator.Int64{
+						int64validator.AtLeast(0),
+					},
+				},
+				"priority": schema.Int64Attribute{
+					MarkdownDescription: "Apisix gateway upstream node priority, nodes of a lower priority are only used when the higher ones are unavailable. Unset is 0",
+					Optional:            true,
+				},
+				"metadata": schema.MapAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "Apisix gateway upstream node metadata",
+					Optional:            true,
+				},
+			},
+		},
+		MarkdownDescription: "Apisix gateway upstream nodes",
+		Required:            required,
+		Optional:            !required,
+		Validators: []validator.Set{
+			setvalidator.SizeAtLeast(1),
+		},
+	}
+}
+
+func inlineUpstreamSchema(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway upstream type",
+				Optional:            true,
+			},
+			"nodes": upstreamNodesSchema(true),
+			"retries": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "Apisix gateway upstream retries",
+			},
+			"pass_host": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway upstream pass host",
+				Optional:            true,
+			},
+			"upstream_host": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway upstream upstream host",
+				Optional:            true,
+			},
+		},
+		MarkdownDescription: description,
+		Optional:            true,
+	}
+}
+
+func buildInfraInlineUpstream(input *InlineUpstream) *model.Upstream {
+	if input == nil {
+		return nil
+	}
+
+	return &model.Upstream{
+		Type:         input.Type.ValueString(),
+		Nodes:        buildInfraUpstreamNodes(input.Nodes),
+		Retries:      int(input.Retries.ValueInt32()),
+		PassHost:     input.PassHost.ValueString(),
+		UpstreamHost: input.UpstreamHost.ValueString(),
+	}
+}
+
+// buildInlineUpstream converts the inline upstream returned by apisix, type, retries and pass_host left unset
+// stay null against the defaults apisix fills in, like fillUpstreamModel does.
+func buildInlineUpstream(upstream *model.Upstream, prior *InlineUpstream) *InlineUpstream {
+	if upstream == nil {
+		return nil
+	}
+	if prior == nil {
+		prior = &InlineUpstream{}
+	}
+
+	inline := &InlineUpstream{
+		Type:         prior.Type,
+		Nodes:        buildUpstreamNodes(upstream.Nodes, prior.Nodes),
+		Retries:      prior.Retries,
+		PassHost:     prior.PassHost,
+		UpstreamHost: stringValueOrNull(upstream.UpstreamHost),
+	}
+	if !prior.Type.IsNull() || (upstream.Type != "" && upstream.Type != DefaultUpstreamType) {
+		inline.Type = stringValueOrNull(upstream.Type)
+	}
+	if !prior.Retries.IsNull() || upstream.Retries != 0 {
+		inline.Retries = types.Int32Value(int32(upstream.Retries))
+	}
+	if !prior.PassHost.IsNull() || (upstream.PassHost != "" && upstream.PassHost != DefaultPassHost) {
+		inline.PassHost = stringValueOrNull(upstream.PassHost)
+	}
+	return inline
 }
 
 func (r *UpstreamResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -48,6 +226,7 @@ func (r *UpstreamResource) Metadata(ctx context.Context, req resource.MetadataRe
 
 func (r *UpstreamResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "upstream resource",
 
@@ -63,17 +242,90 @@ func (r *UpstreamResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "Apisix gateway upstream type",
 				Optional:            true,
 			},
-			"nodes": schema.ListAttribute{
-				ElementType: types.ListType{
-					ElemType: types.StringType,
+			"nodes": upstreamNodesSchema(false),
+			"service_name": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway upstream service name, the nodes are discovered from the registry of discovery_type. Exactly one of nodes and service_name must be set",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 256),
+					stringvalidator.AlsoRequires(path.MatchRoot("discovery_type")),
 				},
-				MarkdownDescription: "Apisix gateway upstream nodes",
+			},
+			"discovery_type": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway upstream discovery type, must be enabled in the apisix config",
 				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("dns", "consul", "consul_kv", "nacos", "eureka", "kubernetes"),
+					stringvalidator.AlsoRequires(path.MatchRoot("service_name")),
+				},
+			},
+			"discovery_args": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"namespace_id": schema.StringAttribute{
+						MarkdownDescription: "Namespace of the service",
+						Optional:            true,
+					},
+					"group_name": schema.StringAttribute{
+						MarkdownDescription: "Group of the service",
+						Optional:            true,
+					},
+					"metadata": schema.MapAttribute{
+						ElementType:         types.StringType,
+						MarkdownDescription: "Metadata the discovered nodes must have",
+						Optional:            true,
+					},
+				},
+				MarkdownDescription: "Apisix gateway upstream discovery args",
+				Optional:            true,
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRoot("service_name")),
+				},
 			},
 			"retries": schema.Int32Attribute{
 				Optional:            true,
 				MarkdownDescription: "Apisix gateway upstream retries",
 			},
+			"scheme": schema.StringAttribute{
+				MarkdownDescription: "Apisix gateway upstream scheme, tcp, udp, tls and kafka are for stream routes. Default http",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("http"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("http", "https", "grpc", "grpcs", "tcp", "udp", "tls", "kafka"),
+				},
+			},
+			"tls": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"client_cert": schema.StringAttribute{
+						MarkdownDescription: "PEM client certificate presented to the nodes, conflicts with client_cert_id",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("client_key")),
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("client_cert_id")),
+						},
+					},
+					"client_key": schema.StringAttribute{
+						MarkdownDescription: "PEM private key of client_cert",
+						Optional:            true,
+						Sensitive:           true,
+						Validators: []validator.String{
+							stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("client_cert")),
+						},
+					},
+					"client_cert_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the apisix_ssl of type client presented to the nodes, conflicts with client_cert",
+						Optional:            true,
+					},
+					"verify": schema.BoolAttribute{
+						MarkdownDescription: "Verify the certificate of the nodes, default false",
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+					},
+				},
+				MarkdownDescription: "Apisix gateway upstream TLS, for the https, grpcs and tls schemes",
+				Optional:            true,
+			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Apisix gateway upstream name",
 				Optional:            true,
@@ -90,52 +342,321 @@ func (r *UpstreamResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "Apisix gateway upstream upstream host",
 				Optional:            true,
 			},
+			"checks": upstreamChecksSchema(),
 		},
 	}
 }
 
+func (r *UpstreamResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("nodes"),
+			path.MatchRoot("service_name"),
+		),
+	}
+}
+
+func (r *UpstreamResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var scheme types.String
+	var upstreamTLS types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("scheme"), &scheme)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tls"), &upstreamTLS)...)
+	if resp.Diagnostics.HasError() || upstreamTLS.IsNull() || upstreamTLS.IsUnknown() {
+		return
+	}
+
+	if !scheme.IsUnknown() && !slices.Contains(tlsSchemes, scheme.ValueString()) {
+		schemeValue := scheme.ValueString()
+		if scheme.IsNull() {
+			schemeValue = "http"
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("tls"),
+			"TLS of a plain scheme",
+			fmt.Sprintf("Attribute tls requires scheme to be one of %v, got scheme %s.", tlsSchemes, schemeValue),
+		)
+	}
+
+	var clientCert, clientKey types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tls").AtName("client_cert"), &clientCert)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tls").AtName("client_key"), &clientKey)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	validateCertificate(path.Root("tls").AtName("client_cert"), path.Root("tls").AtName("client_key"), clientCert, clientKey, &resp.Diagnostics)
+}
+
+func (r *UpstreamResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: upgradeUpstreamNodesState("nodes"),
+	}
+}
+
 func (r *UpstreamResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*api.ApisixClient)
+	providerData, ok := req.ProviderData.(*ApisixProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *api.ApisixClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ApisixProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Client
+}
+
+func buildInfraUpstreamNodes(nodes []UpstreamNode) []model.UpstreamNode {
+	if nodes == nil {
+		return nil
+	}
+
+	infraNodes := make([]model.UpstreamNode, 0, len(nodes))
+	for _, node := range nodes {
+		infraNodes = append(infraNodes, model.UpstreamNode{
+			Host:     node.Host.ValueString(),
+			Port:     int(node.Port.ValueInt64()),
+			Weight:   int(node.Weight.ValueInt64()),
+			Priority: int(node.Priority.ValueInt64()),
+			Metadata: node.Metadata,
+		})
+	}
+	sortUpstreamNodes(infraNodes)
+	return infraNodes
+}
+
+// buildUpstreamNodes converts the nodes returned by apisix, a priority of 0 is kept
+// from prior as apisix does not tell it apart from an unset one.
+func buildUpstreamNodes(nodes []model.UpstreamNode, prior []UpstreamNode) []UpstreamNode {
+	if nodes == nil {
+		return nil
+	}
+
+	priorPriorities := make(map[string]types.Int64, len(prior))
+	for _, node := range prior {
+		priorPriorities[upstreamNodeAddress(node.Host.ValueString(), int(node.Port.ValueInt64()))] = node.Priority
+	}
+
+	// Sort a copy, the nodes belong to the apisix response
+	nodes = append([]model.UpstreamNode(nil), nodes...)
+	sortUpstreamNodes(nodes)
+
+	built := make([]UpstreamNode, 0, len(nodes))
+	for _, node := range nodes {
+		priority := int64ValueOrNull(node.Priority)
+		if priorPriority, ok := priorPriorities[upstreamNodeAddress(node.Host, node.Port)]; ok && node.Priority == 0 {
+			priority = priorPriority
+		}
+		built = append(built, UpstreamNode{
+			Host:     types.StringValue(node.Host),
+			Port:     types.Int64Value(int64(node.Port)),
+			Weight:   types.Int64Value(int64(node.Weight)),
+			Priority: priority,
+			Metadata: node.Metadata,
+		})
+	}
+	return built
+}
+
+// sortUpstreamNodes puts nodes in a canonical order, apisix may return them in any order,
+// like when they were created as a host:port map.
+func sortUpstreamNodes(nodes []model.UpstreamNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.Weight < b.Weight
+	})
+}
+
+func upstreamNodeAddress(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// upgradeUpstreamNodesState returns a state upgrader from schema version 0, which held nodes
+// as [host, port, weight] string lists, to the node objects. path leads to the nodes in the state.
+func upgradeUpstreamNodesState(path ...string) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			var rawState map[string]any
+			if err := json.Unmarshal(req.RawState.JSON, &rawState); err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Upgrade Resource State",
+					"Could not read the prior state, unexpected error: "+err.Error(),
+				)
+				return
+			}
+
+			if err := upgradeUpstreamNodes(rawState, path); err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Upgrade Resource State",
+					"Could not upgrade the upstream nodes of the prior state: "+err.Error(),
+				)
+				return
+			}
+
+			upgradedState, err := json.Marshal(rawState)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Upgrade Resource State",
+					"Could not write the upgraded state, unexpected error: "+err.Error(),
+				)
+				return
+			}
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgradedState}
+		},
+	}
+}
+
+func upgradeUpstreamNodes(rawState map[string]any, path []string) error {
+	parent := rawState
+	for _, name := range path[:len(path)-1] {
+		child, ok := parent[name].(map[string]any)
+		if !ok {
+			// The upstream is not set
+			return nil
+		}
+		parent = child
+	}
+
+	name := path[len(path)-1]
+	nodes, ok := parent[name].([]any)
+	if !ok {
+		return nil
+	}
+
+	upgradedNodes := make([]any, 0, len(nodes))
+	for i, node := range nodes {
+		fields, ok := node.([]any)
+		if !ok || len(fields) != 3 {
+			return fmt.Errorf("%s.%d must be a [host, port, weight] list, got: %v", name, i, node)
+		}
+		host, _ := fields[0].(string)
+		port, err := strconv.Atoi(fmt.Sprint(fields[1]))
+		if err != nil {
+			return fmt.Errorf("port of %s.%d must be a number: %w", name, i, err)
+		}
+		weight, err := strconv.Atoi(fmt.Sprint(fields[2]))
+		if err != nil {
+			return fmt.Errorf("weight of %s.%d must be a number: %w", name, i, err)
+		}
+		upgradedNodes = append(upgradedNodes, map[string]any{
+			"host":     host,
+			"port":     port,
+			"weight":   weight,
+			"priority": nil,
+			"metadata": nil,
+		})
+	}
+	parent[name] = upgradedNodes
+	return nil
+}
+
+func buildInfraUpstream(data *UpstreamResourceModel) *model.Upstream {
+	return &model.Upstream{
+		ID:            data.ID.ValueString(),
+		Type:          data.Type.ValueString(),
+		Nodes:         buildInfraUpstreamNodes(data.Nodes),
+		Retries:       int(data.Retries.ValueInt32()),
+		Name:          data.Name.ValueString(),
+		Desc:          data.Desc.ValueString(),
+		PassHost:      data.PassHost.ValueString(),
+		UpstreamHost:  data.UpstreamHost.ValueString(),
+		Checks:        buildInfraUpstreamChecks(data.Checks),
+		ServiceName:   data.ServiceName.ValueString(),
+		DiscoveryType: data.DiscoveryType.ValueString(),
+		DiscoveryArgs: buildInfraUpstreamDiscoveryArgs(data.DiscoveryArgs),
+		Scheme:        data.Scheme.ValueString(),
+		TLS:           buildInfraUpstreamTLS(data.TLS),
+	}
+}
+
+func buildInfraUpstreamTLS(tls *UpstreamTLS) *model.UpstreamTLS {
+	if tls == nil {
+		return nil
+	}
+	return &model.UpstreamTLS{
+		ClientCert:   tls.ClientCert.ValueString(),
+		ClientKey:    tls.ClientKey.ValueString(),
+		ClientCertId: tls.ClientCertId.ValueString(),
+		Verify:       tls.Verify.ValueBool(),
+	}
+}
+
+// buildUpstreamTLS converts the TLS returned by apisix, client_key of prior is kept
+// as apisix returns it encrypted.
+func buildUpstreamTLS(tls *model.UpstreamTLS, prior *UpstreamTLS) *UpstreamTLS {
+	if tls == nil {
+		return nil
+	}
+	built := &UpstreamTLS{
+		ClientCert:   stringValueOrNull(tls.ClientCert),
+		ClientKey:    stringValueOrNull(tls.ClientKey),
+		ClientCertId: stringValueOrNull(tls.ClientCertId),
+		Verify:       types.BoolValue(tls.Verify),
+	}
+	if prior != nil && !prior.ClientKey.IsNull() && tls.ClientKey != "" {
+		built.ClientKey = prior.ClientKey
+	}
+	return built
+}
+
+func buildInfraUpstreamDiscoveryArgs(args *UpstreamDiscoveryArgs) *model.UpstreamDiscoveryArgs {
+	if args == nil {
+		return nil
+	}
+	return &model.UpstreamDiscoveryArgs{
+		NamespaceId: args.NamespaceId.ValueString(),
+		GroupName:   args.GroupName.ValueString(),
+		Metadata:    args.Metadata,
+	}
 }
 
-func arrayToMap(arrays [][]string) map[string]int {
-	mappings := make(map[string]int)
-	for _, array := range arrays {
-		host := array[0]
-		port := array[1]
-		priority, _ := strconv.Atoi(array[2])
-		mappings[host+":"+port] = priority
+func buildUpstreamDiscoveryArgs(args *model.UpstreamDiscoveryArgs) *UpstreamDiscoveryArgs {
+	if args == nil {
+		return nil
+	}
+	return &UpstreamDiscoveryArgs{
+		NamespaceId: stringValueOrNull(args.NamespaceId),
+		GroupName:   stringValueOrNull(args.GroupName),
+		Metadata:    args.Metadata,
 	}
-	return mappings
 }
 
-func mapToArray(mappings map[string]int) [][]string {
-	arrays := make([][]string, 0, len(mappings))
-	for k, v := range mappings {
-		array := make([]string, 0, 3)
-		host := strings.Split(k, ":")[0]
-		port := strings.Split(k, ":")[1]
-		priority := strconv.Itoa(v)
-		array = append(array, host)
-		array = append(array, port)
-		array = append(array, priority)
-		arrays = append(arrays, array)
+func fillUpstreamModel(data *UpstreamResourceModel, upstream *model.Upstream) {
+	data.ID = types.StringValue(upstream.ID)
+	data.Nodes = buildUpstreamNodes(upstream.Nodes, data.Nodes)
+	// retries, pass_host and upstream_host left unset stay null, apisix returns its defaults for them
+	if !data.Retries.IsNull() || upstream.Retries != 0 {
+		data.Retries = types.Int32Value(int32(upstream.Retries))
+	}
+	data.Name = stringValueOrNull(upstream.Name)
+	data.Desc = stringValueOrNull(upstream.Desc)
+	if !data.PassHost.IsNull() || (upstream.PassHost != "" && upstream.PassHost != DefaultPassHost) {
+		data.PassHost = stringValueOrNull(upstream.PassHost)
+	}
+	if upstream.PassHost == RewriteUpstreamHost {
+		data.UpstreamHost = stringValueOrNull(upstream.UpstreamHost)
+	} else if !data.UpstreamHost.IsNull() {
+		data.UpstreamHost = types.StringValue(InvalidUpstreamHost)
 	}
-	return arrays
+	data.Checks = buildUpstreamChecks(upstream.Checks, data.Checks)
+	data.ServiceName = stringValueOrNull(upstream.ServiceName)
+	data.DiscoveryType = stringValueOrNull(upstream.DiscoveryType)
+	data.DiscoveryArgs = buildUpstreamDiscoveryArgs(upstream.DiscoveryArgs)
+	data.Scheme = types.StringValue(upstream.Scheme)
+	data.TLS = buildUpstreamTLS(upstream.TLS, data.TLS)
 }
 
 func (r *UpstreamResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -148,16 +669,7 @@ func (r *UpstreamResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	// Generate API request body from plan
-	upstream := &model.Upstream{
-		ID:           data.ID.ValueString(),
-		Type:         data.Type.ValueString(),
-		Nodes:        arrayToMap(data.Nodes),
-		Retries:      int(data.Retries.ValueInt32()),
-		Name:         data.Name.ValueString(),
-		Desc:         data.Desc.ValueString(),
-		PassHost:     data.PassHost.ValueString(),
-		UpstreamHost: data.UpstreamHost.ValueString(),
-	}
+	upstream := buildInfraUpstream(&data)
 
 	createdUpstream, err := r.client.CreateUpstreams(upstream)
 	if err != nil {
@@ -170,16 +682,7 @@ func (r *UpstreamResource) Create(ctx context.Context, req resource.CreateReques
 
 	// Update the plan with create API response and save it to .tfstate file
 	// API response reflects the latest state of the route
-	data.ID = types.StringValue(createdUpstream.ID)
-	data.Nodes = mapToArray(createdUpstream.Nodes)
-	data.Retries = types.Int32Value(int32(createdUpstream.Retries))
-	data.Name = types.StringValue(createdUpstream.Name)
-	data.Desc = types.StringValue(createdUpstream.Desc)
-	data.PassHost = types.StringValue(createdUpstream.PassHost)
-	data.UpstreamHost = types.StringValue(createdUpstream.UpstreamHost)
-	if createdUpstream.PassHost != RewriteUpstreamHost {
-		data.UpstreamHost = types.StringValue(InvalidUpstreamHost)
-	}
+	fillUpstreamModel(&data, createdUpstream)
 
 	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
 	// Save data into Terraform state
@@ -205,16 +708,7 @@ func (r *UpstreamResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	data.ID = types.StringValue(fetchedUpstream.ID)
-	data.Nodes = mapToArray(fetchedUpstream.Nodes)
-	data.Retries = types.Int32Value(int32(fetchedUpstream.Retries))
-	data.Name = types.StringValue(fetchedUpstream.Name)
-	data.Desc = types.StringValue(fetchedUpstream.Desc)
-	data.PassHost = types.StringValue(fetchedUpstream.PassHost)
-	data.UpstreamHost = types.StringValue(fetchedUpstream.UpstreamHost)
-	if fetchedUpstream.PassHost != RewriteUpstreamHost {
-		data.UpstreamHost = types.StringValue(InvalidUpstreamHost)
-	}
+	fillUpstreamModel(&data, fetchedUpstream)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -229,16 +723,7 @@ func (r *UpstreamResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	// Generate API request body from plan
-	upstream := &model.Upstream{
-		ID:           data.ID.ValueString(),
-		Type:         data.Type.ValueString(),
-		Nodes:        arrayToMap(data.Nodes),
-		Retries:      int(data.Retries.ValueInt32()),
-		Name:         data.Name.ValueString(),
-		Desc:         data.Desc.ValueString(),
-		PassHost:     data.PassHost.ValueString(),
-		UpstreamHost: data.UpstreamHost.ValueString(),
-	}
+	upstream := buildInfraUpstream(&data)
 
 	createdUpstream, err := r.client.UpdateUpstream(upstream)
 	if err != nil {
@@ -251,16 +736,7 @@ func (r *UpstreamResource) Update(ctx context.Context, req resource.UpdateReques
 
 	// Update the plan with create API response and save it to .tfstate file
 	// API response reflects the latest state of the route
-	data.ID = types.StringValue(createdUpstream.ID)
-	data.Nodes = mapToArray(createdUpstream.Nodes)
-	data.Retries = types.Int32Value(int32(createdUpstream.Retries))
-	data.Name = types.StringValue(createdUpstream.Name)
-	data.Desc = types.StringValue(createdUpstream.Desc)
-	data.PassHost = types.StringValue(createdUpstream.PassHost)
-	data.UpstreamHost = types.StringValue(createdUpstream.UpstreamHost)
-	if createdUpstream.PassHost != RewriteUpstreamHost {
-		data.UpstreamHost = types.StringValue(InvalidUpstreamHost)
-	}
+	fillUpstreamModel(&data, createdUpstream)
 
 	tflog.Trace(ctx, "created a resource "+data.ID.ValueString())
 	// Save data into Terraform state
@@ -1,10 +1,22 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
 	"os"
+	"reflect"
+	"regexp"
 	"silas.com/ssf-terraform/apisix-client/api"
+	"silas.com/ssf-terraform/apisix-client/model"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -21,7 +33,11 @@ func TestApisixUpstreamResource(t *testing.T) {
 resource "apisix_upstream" "common" {
     id = "common"
     type = "roundbin"
-    nodes = [["127.0.0.1", "80", "1"]]
+    nodes = [{
+      host = "127.0.0.1"
+      port = 80
+      weight = 1
+    }]
     retries = 3
     name = "common"
     desc = "Common upstream for all services, forward requests to ingress"
@@ -32,7 +48,11 @@ resource "apisix_upstream" "common" {
 
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("apisix_upstream.common", "id", "common"),
-					resource.TestCheckResourceAttr("apisix_upstream.common", "nodes.0.0", "127.0.0.1"),
+					resource.TestCheckTypeSetElemNestedAttrs("apisix_upstream.common", "nodes.*", map[string]string{
+						"host":   "127.0.0.1",
+						"port":   "80",
+						"weight": "1",
+					}),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "retries", "3"),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "name", "common"),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "desc", "Common upstream for all services, forward requests to ingress"),
@@ -44,25 +64,527 @@ resource "apisix_upstream" "common" {
 resource "apisix_upstream" "common" {
     id = "common"
     type = "roundbin"
-    nodes = [["127.0.0.1", "80", "1"]]
+    nodes = [
+      {
+        host = "127.0.0.1"
+        port = 80
+        weight = 1
+      },
+      {
+        host = "::1"
+        port = 8080
+        weight = 1
+        priority = -1
+        metadata = {
+          zone = "backup"
+        }
+      },
+    ]
     retries = 1
     name = "common"
     desc = "Common upstream for all services, forward requests to ingress"
     pass_host = "rewrite"
     upstream_host = "127.0.0.2:80"
+    checks = {
+      active = {
+        http_path = "/healthz"
+        req_headers = ["User-Agent: apisix-health-check"]
+        healthy = {
+          interval = 2
+          successes = 1
+        }
+        unhealthy = {
+          interval = 1
+          http_statuses = [500, 502, 503]
+        }
+      }
+      passive = {
+        unhealthy = {
+          http_failures = 3
+        }
+      }
+    }
  }
 `,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("apisix_upstream.common", "id", "common"),
-					resource.TestCheckResourceAttr("apisix_upstream.common", "nodes.0.0", "127.0.0.1"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "nodes.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("apisix_upstream.common", "nodes.*", map[string]string{
+						"host":          "::1",
+						"port":          "8080",
+						"priority":      "-1",
+						"metadata.zone": "backup",
+					}),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "retries", "1"),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "name", "common"),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "desc", "Common upstream for all services, forward requests to ingress"),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "pass_host", "rewrite"),
 					resource.TestCheckResourceAttr("apisix_upstream.common", "upstream_host", "127.0.0.2:80"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.active.type", "http"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.active.http_path", "/healthz"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.active.healthy.interval", "2"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.common", "checks.active.healthy.http_statuses"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.active.unhealthy.http_statuses.#", "3"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.active.unhealthy.tcp_failures", "2"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.passive.unhealthy.http_failures", "3"),
+					resource.TestCheckResourceAttr("apisix_upstream.common", "checks.passive.unhealthy.timeouts", "7"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.common", "checks.passive.healthy"),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
+
+func TestApisixUpstreamResourceDiscovery(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_upstream" "discovery" {
+    id = "discovery"
+    type = "roundrobin"
+    service_name = "ssf-demo"
+    discovery_type = "nacos"
+    discovery_args = {
+      namespace_id = "prod"
+      group_name = "DEFAULT_GROUP"
+    }
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_upstream.discovery", "service_name", "ssf-demo"),
+					resource.TestCheckResourceAttr("apisix_upstream.discovery", "discovery_type", "nacos"),
+					resource.TestCheckResourceAttr("apisix_upstream.discovery", "discovery_args.namespace_id", "prod"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.discovery", "nodes"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.discovery", "retries"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.discovery", "pass_host"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "apisix_upstream" "discovery" {
+    id = "discovery"
+    type = "roundrobin"
+    service_name = "ssf-demo.default.svc.cluster.local"
+    discovery_type = "kubernetes"
+ }
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_upstream.discovery", "service_name", "ssf-demo.default.svc.cluster.local"),
+					resource.TestCheckResourceAttr("apisix_upstream.discovery", "discovery_type", "kubernetes"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.discovery", "discovery_args"),
+				),
+			},
+			// Static nodes and discovery are rejected together
+			{
+				Config: providerConfig + `
+resource "apisix_upstream" "discovery" {
+    id = "discovery"
+    service_name = "ssf-demo"
+    discovery_type = "nacos"
+    nodes = [{
+      host = "127.0.0.1"
+      port = 80
+      weight = 1
+    }]
+ }
+`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			// A service name needs a discovery type
+			{
+				Config: providerConfig + `
+resource "apisix_upstream" "discovery" {
+    id = "discovery"
+    service_name = "ssf-demo"
+ }
+`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestApisixUpstreamResourceTLS(t *testing.T) {
+	os.Setenv(api.ApisixHost, "http://172.18.21.239:9180")
+	os.Setenv(api.ApisixKey, "api-key")
+
+	cert, key := generateCertificate(t, time.Now().Add(90*24*time.Hour), "apisix.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "apisix_upstream" "mtls" {
+    id = "mtls"
+    scheme = "https"
+    nodes = [{
+      host = "127.0.0.1"
+      port = 443
+      weight = 1
+    }]
+    tls = {
+      client_cert = <<EOT
+%sEOT
+      client_key = <<EOT
+%sEOT
+      verify = true
+    }
+ }
+`, cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "scheme", "https"),
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "tls.client_cert", cert),
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "tls.client_key", key),
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "tls.verify", "true"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "apisix_ssl" "client" {
+    id = "client"
+    type = "client"
+    cert = <<EOT
+%sEOT
+    key = <<EOT
+%sEOT
+ }
+
+resource "apisix_upstream" "mtls" {
+    id = "mtls"
+    scheme = "grpcs"
+    nodes = [{
+      host = "127.0.0.1"
+      port = 443
+      weight = 1
+    }]
+    tls = {
+      client_cert_id = apisix_ssl.client.id
+    }
+ }
+`, cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "scheme", "grpcs"),
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "tls.client_cert_id", "client"),
+					resource.TestCheckResourceAttr("apisix_upstream.mtls", "tls.verify", "false"),
+					resource.TestCheckNoResourceAttr("apisix_upstream.mtls", "tls.client_key"),
+				),
+			},
+			// An inline client certificate conflicts with client_cert_id
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "apisix_upstream" "mtls" {
+    id = "mtls"
+    scheme = "https"
+    nodes = [{
+      host = "127.0.0.1"
+      port = 443
+      weight = 1
+    }]
+    tls = {
+      client_cert = <<EOT
+%sEOT
+      client_key = <<EOT
+%sEOT
+      client_cert_id = "client"
+    }
+ }
+`, cert, key),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			// TLS needs a TLS scheme
+			{
+				Config: providerConfig + `
+resource "apisix_upstream" "mtls" {
+    id = "mtls"
+    scheme = "http"
+    nodes = [{
+      host = "127.0.0.1"
+      port = 443
+      weight = 1
+    }]
+    tls = {
+      client_cert_id = "client"
+    }
+ }
+`,
+				ExpectError: regexp.MustCompile("TLS of a plain scheme"),
+			},
+			// The inline client certificate must be a PEM certificate of client_key
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "apisix_upstream" "mtls" {
+    id = "mtls"
+    scheme = "https"
+    nodes = [{
+      host = "127.0.0.1"
+      port = 443
+      weight = 1
+    }]
+    tls = {
+      client_cert = "not a certificate"
+      client_key = <<EOT
+%sEOT
+    }
+ }
+`, key),
+				ExpectError: regexp.MustCompile("Invalid certificate"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestUpstreamResourceUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	r := NewUpstreamResource()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	upgrader := r.(fwresource.ResourceWithUpgradeState).UpgradeState(ctx)[0]
+
+	cases := map[string]struct {
+		rawState string
+		nodes    []map[string]any
+		valid    bool
+	}{
+		"nodes": {
+			rawState: `{"id":"common","type":"roundrobin","nodes":[["127.0.0.1","80","1"],["fd00::1","8080","0"]],"retries":3,"name":"common","desc":null,"pass_host":"pass","upstream_host":"invalid"}`,
+			nodes: []map[string]any{
+				{"host": "127.0.0.1", "port": 80, "weight": 1},
+				{"host": "fd00::1", "port": 8080, "weight": 0},
+			},
+			valid: true,
+		},
+		"no nodes": {
+			rawState: `{"id":"common","type":null,"nodes":null,"retries":0,"name":"common","desc":"","pass_host":"","upstream_host":"invalid"}`,
+			valid:    true,
+		},
+		"invalid weight": {
+			rawState: `{"id":"common","nodes":[["127.0.0.1","80","heavy"]]}`,
+		},
+		"invalid port": {
+			rawState: `{"id":"common","nodes":[["127.0.0.1","http","1"]]}`,
+		},
+		"missing weight": {
+			rawState: `{"id":"common","nodes":[["127.0.0.1","80"]]}`,
+		},
+	}
+
+	for name, c := range cases {
+		req := fwresource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(c.rawState)}}
+		resp := &fwresource.UpgradeStateResponse{}
+		upgrader.StateUpgrader(ctx, req, resp)
+		if resp.Diagnostics.HasError() == c.valid {
+			t.Errorf("%s: expected valid=%v, got diagnostics %v", name, c.valid, resp.Diagnostics)
+			continue
+		}
+		if !c.valid {
+			continue
+		}
+
+		upgraded, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+		if err != nil {
+			t.Errorf("%s: upgraded state does not match the schema: %v", name, err)
+			continue
+		}
+		var state map[string]tftypes.Value
+		if err := upgraded.As(&state); err != nil {
+			t.Fatal(err)
+		}
+		var nodes []tftypes.Value
+		if err := state["nodes"].As(&nodes); err != nil {
+			t.Fatal(err)
+		}
+		if len(nodes) != len(c.nodes) {
+			t.Errorf("%s: expected %d nodes, got %d", name, len(c.nodes), len(nodes))
+		}
+		for _, expected := range c.nodes {
+			found := false
+			for _, node := range nodes {
+				var attributes map[string]tftypes.Value
+				_ = node.As(&attributes)
+				var host string
+				var port, weight big.Float
+				_ = attributes["host"].As(&host)
+				_ = attributes["port"].As(&port)
+				_ = attributes["weight"].As(&weight)
+				portValue, _ := port.Int64()
+				weightValue, _ := weight.Int64()
+				if host == expected["host"] && int(portValue) == expected["port"] && int(weightValue) == expected["weight"] && attributes["priority"].IsNull() {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: node %v not found in %v", name, expected, nodes)
+			}
+		}
+	}
+}
+
+func TestBuildUpstreamNodes(t *testing.T) {
+	nodes := []model.UpstreamNode{
+		{Host: "127.0.0.1", Port: 80, Weight: 1},
+		{Host: "fd00::1", Port: 80, Weight: 1},
+		{Host: "fd00::1", Port: 8080, Weight: 1, Priority: -1},
+	}
+	prior := []UpstreamNode{
+		{Host: types.StringValue("fd00::1"), Port: types.Int64Value(80), Weight: types.Int64Value(1), Priority: types.Int64Value(0)},
+	}
+
+	built := buildUpstreamNodes(nodes, prior)
+	if len(built) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(built))
+	}
+	if !built[0].Priority.IsNull() {
+		t.Errorf("expected unset priority to be null, got %s", built[0].Priority)
+	}
+	if built[1].Priority != types.Int64Value(0) {
+		t.Errorf("expected priority 0 of prior to be kept, got %s", built[1].Priority)
+	}
+	if built[2].Priority != types.Int64Value(-1) {
+		t.Errorf("expected priority -1, got %s", built[2].Priority)
+	}
+	if built[1].Host.ValueString() != "fd00::1" || built[1].Port.ValueInt64() != 80 {
+		t.Errorf("expected IPv6 node fd00::1 port 80, got %s port %s", built[1].Host, built[1].Port)
+	}
+}
+
+func TestFillUpstreamModel(t *testing.T) {
+	upstream := &model.Upstream{
+		ID:            "discovery",
+		ServiceName:   "ssf-demo",
+		DiscoveryType: "nacos",
+		PassHost:      DefaultPassHost,
+		Scheme:        "http",
+	}
+
+	// Unset attributes stay null against the defaults apisix returns
+	data := &UpstreamResourceModel{}
+	fillUpstreamModel(data, upstream)
+	for name, value := range map[string]interface{ IsNull() bool }{
+		"retries":       data.Retries,
+		"name":          data.Name,
+		"desc":          data.Desc,
+		"pass_host":     data.PassHost,
+		"upstream_host": data.UpstreamHost,
+	} {
+		if !value.IsNull() {
+			t.Errorf("expected unset %s to be null, got %v", name, value)
+		}
+	}
+
+	// Configured attributes are read back, including the zero retries
+	data = &UpstreamResourceModel{
+		Retries:      types.Int32Value(0),
+		PassHost:     types.StringValue(DefaultPassHost),
+		UpstreamHost: types.StringValue(InvalidUpstreamHost),
+	}
+	fillUpstreamModel(data, upstream)
+	if data.Retries != types.Int32Value(0) || data.PassHost != types.StringValue(DefaultPassHost) || data.UpstreamHost != types.StringValue(InvalidUpstreamHost) {
+		t.Errorf("expected configured attributes to be kept, got retries %s, pass_host %s, upstream_host %s", data.Retries, data.PassHost, data.UpstreamHost)
+	}
+
+	upstream.PassHost = RewriteUpstreamHost
+	upstream.UpstreamHost = "ssf-demo.example.com"
+	data = &UpstreamResourceModel{}
+	fillUpstreamModel(data, upstream)
+	if data.PassHost != types.StringValue(RewriteUpstreamHost) || data.UpstreamHost != types.StringValue("ssf-demo.example.com") {
+		t.Errorf("expected pass_host and upstream_host changed outside of terraform, got %s and %s", data.PassHost, data.UpstreamHost)
+	}
+}
+
+func TestBuildInlineUpstream(t *testing.T) {
+	upstream := &model.Upstream{
+		Type:     DefaultUpstreamType,
+		Nodes:    []model.UpstreamNode{{Host: "127.0.0.1", Port: 80, Weight: 1}},
+		PassHost: DefaultPassHost,
+	}
+
+	// Unset attributes stay null against the defaults apisix returns, on import as well
+	for name, prior := range map[string]*InlineUpstream{"unset": {}, "import": nil} {
+		inline := buildInlineUpstream(upstream, prior)
+		if !inline.Type.IsNull() || !inline.Retries.IsNull() || !inline.PassHost.IsNull() || !inline.UpstreamHost.IsNull() {
+			t.Errorf("%s: expected unset attributes to be null, got type %s, retries %s, pass_host %s, upstream_host %s",
+				name, inline.Type, inline.Retries, inline.PassHost, inline.UpstreamHost)
+		}
+	}
+
+	// Configured attributes are read back, including the zero retries
+	inline := buildInlineUpstream(upstream, &InlineUpstream{
+		Type:     types.StringValue(DefaultUpstreamType),
+		Retries:  types.Int32Value(0),
+		PassHost: types.StringValue(DefaultPassHost),
+	})
+	if inline.Type != types.StringValue(DefaultUpstreamType) || inline.Retries != types.Int32Value(0) || inline.PassHost != types.StringValue(DefaultPassHost) {
+		t.Errorf("expected configured attributes to be kept, got type %s, retries %s, pass_host %s", inline.Type, inline.Retries, inline.PassHost)
+	}
+
+	// Values other than the defaults are drift
+	upstream.Type = "chash"
+	upstream.Retries = 2
+	upstream.PassHost = RewriteUpstreamHost
+	upstream.UpstreamHost = "ssf-demo.example.com"
+	inline = buildInlineUpstream(upstream, &InlineUpstream{})
+	if inline.Type != types.StringValue("chash") || inline.Retries != types.Int32Value(2) || inline.PassHost != types.StringValue(RewriteUpstreamHost) || inline.UpstreamHost != types.StringValue("ssf-demo.example.com") {
+		t.Errorf("expected attributes changed outside of terraform, got type %s, retries %s, pass_host %s, upstream_host %s",
+			inline.Type, inline.Retries, inline.PassHost, inline.UpstreamHost)
+	}
+}
+
+func TestUpstreamNodesOrder(t *testing.T) {
+	ctx := context.Background()
+	var nodes []model.UpstreamNode
+	for i := 0; i < 50; i++ {
+		nodes = append(nodes,
+			model.UpstreamNode{Host: fmt.Sprintf("10.0.%d.%d", i%7, i), Port: 80 + i%3, Weight: i % 5},
+			model.UpstreamNode{Host: fmt.Sprintf("fd00::%x", i), Port: 8080, Weight: 1, Priority: i % 2},
+			model.UpstreamNode{Host: fmt.Sprintf("node-%d.example.com", i%10), Port: 443 + i, Weight: 1},
+		)
+	}
+	expected := buildUpstreamNodes(nodes, nil)
+	expectedSet, diags := types.SetValueFrom(ctx, upstreamNodeObjectType(), expected)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		shuffled := append([]model.UpstreamNode(nil), nodes...)
+		random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		built := buildUpstreamNodes(shuffled, nil)
+		if !reflect.DeepEqual(built, expected) {
+			t.Fatalf("round %d: nodes are not in a deterministic order", round)
+		}
+		builtSet, _ := types.SetValueFrom(ctx, upstreamNodeObjectType(), built)
+		if !builtSet.Equal(expectedSet) {
+			t.Fatalf("round %d: nodes are not equal as a set", round)
+		}
+
+		infraNodes := buildInfraUpstreamNodes(built)
+		for i := 1; i < len(infraNodes); i++ {
+			previous, node := infraNodes[i-1], infraNodes[i]
+			if previous.Host > node.Host || (previous.Host == node.Host && previous.Port > node.Port) {
+				t.Fatalf("round %d: node %d %s:%d is sorted after %s:%d", round, i, node.Host, node.Port, previous.Host, previous.Port)
+			}
+		}
+	}
+	if nodes[0].Host != "10.0.0.0" || nodes[1].Host != "fd00::0" {
+		t.Errorf("expected the nodes of the apisix response to be left as is, got %s, %s", nodes[0].Host, nodes[1].Host)
+	}
+}
+
+func upstreamNodeObjectType() types.ObjectType {
+	return upstreamNodesSchema(true).NestedObject.Type().(types.ObjectType)
+}
@@ -0,0 +1,349 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// httpUrlRegex matches http(s) URLs of endpoints apisix connects to.
+var httpUrlRegex = regexp.MustCompile(`^https?://[^\s]+$`)
+
+var _ validator.String = ipAddressValidator{}
+
+// ipAddressValidator validates that a string is an IPv4 or IPv6 address, or a CIDR when allowCIDR is set.
+// Only IPv4 is accepted when ipv4Only is set.
+type ipAddressValidator struct {
+	allowCIDR bool
+	ipv4Only  bool
+}
+
+func (v ipAddressValidator) Description(ctx context.Context) string {
+	version := "IPv4/IPv6"
+	if v.ipv4Only {
+		version = "IPv4"
+	}
+	if v.allowCIDR {
+		return "value must be an " + version + " address or CIDR"
+	}
+	return "value must be an " + version + " address"
+}
+
+func (v ipAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if ip := net.ParseIP(value); ip != nil && (!v.ipv4Only || ip.To4() != nil) {
+		return
+	}
+	if v.allowCIDR {
+		if ip, _, err := net.ParseCIDR(value); err == nil && (!v.ipv4Only || ip.To4() != nil) {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid address",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+// isIPAddress returns a validator which ensures the value is an IP address.
+func isIPAddress() validator.String {
+	return ipAddressValidator{}
+}
+
+// isIPv4Address returns a validator which ensures the value is an IPv4 address.
+func isIPv4Address() validator.String {
+	return ipAddressValidator{ipv4Only: true}
+}
+
+// isIPAddressOrCIDR returns a validator which ensures the value is an IP address or CIDR.
+func isIPAddressOrCIDR() validator.String {
+	return ipAddressValidator{allowCIDR: true}
+}
+
+var _ validator.Object = limitCountPolicyValidator{}
+
+// limitCountPolicyValidator validates that the redis settings of limit-count match its policy.
+type limitCountPolicyValidator struct{}
+
+func (v limitCountPolicyValidator) Description(ctx context.Context) string {
+	return "redis must be set exactly when policy is redis, redis_cluster exactly when policy is redis-cluster"
+}
+
+func (v limitCountPolicyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v limitCountPolicyValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	attributes := req.ConfigValue.Attributes()
+	policy, ok := attributes["policy"].(types.String)
+	if !ok || policy.IsUnknown() {
+		return
+	}
+	policyValue := policy.ValueString()
+	if policy.IsNull() {
+		policyValue = LimitCountPolicyLocal
+	}
+
+	for name, blockPolicy := range map[string]string{
+		"redis":         LimitCountPolicyRedis,
+		"redis_cluster": LimitCountPolicyRedisCluster,
+	} {
+		block := attributes[name]
+		if block == nil || block.IsUnknown() {
+			continue
+		}
+		if policyValue == blockPolicy && block.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtName(name),
+				"Missing limit-count "+name,
+				fmt.Sprintf("Attribute %s must be set when policy is %s.", req.Path.AtName(name), blockPolicy),
+			)
+		}
+		if policyValue != blockPolicy && !block.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtName(name),
+				"Unused limit-count "+name,
+				fmt.Sprintf("Attribute %s is only used when policy is %s, got policy %s.", req.Path.AtName(name), blockPolicy, policyValue),
+			)
+		}
+	}
+}
+
+// malformedRegexErrors are the parse errors of patterns that are invalid in PCRE as well. Others, like lookarounds,
+// backreferences or possessive quantifiers, are only unsupported by RE2 and accepted as apisix matches with PCRE.
+var malformedRegexErrors = map[syntax.ErrorCode]bool{
+	syntax.ErrMissingParen:          true,
+	syntax.ErrUnexpectedParen:       true,
+	syntax.ErrMissingBracket:        true,
+	syntax.ErrInvalidCharRange:      true,
+	syntax.ErrTrailingBackslash:     true,
+	syntax.ErrMissingRepeatArgument: true,
+	syntax.ErrInvalidUTF8:           true,
+}
+
+// checkRegex returns an error when pattern is malformed, the check is best effort as RE2 stops at the first error.
+func checkRegex(pattern string) error {
+	_, err := syntax.Parse(pattern, syntax.Perl)
+	if parseErr, ok := err.(*syntax.Error); ok && !malformedRegexErrors[parseErr.Code] {
+		return nil
+	}
+	return err
+}
+
+var _ validator.String = regexValidator{}
+
+// regexValidator validates that a string is a well formed PCRE regex.
+type regexValidator struct{}
+
+func (v regexValidator) Description(ctx context.Context) string {
+	return "value must be a well formed regex"
+}
+
+func (v regexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := checkRegex(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid regex",
+			fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err),
+		)
+	}
+}
+
+var _ validator.List = regexUriValidator{}
+
+// regexUriValidator validates regex_uri lists of regex and replacement pairs, like ["^/iresty/(.*)", "/$1"].
+type regexUriValidator struct{}
+
+func (v regexUriValidator) Description(ctx context.Context) string {
+	return "value must be pairs of a well formed regex followed by its replacement"
+}
+
+func (v regexUriValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexUriValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	elements := req.ConfigValue.Elements()
+	if len(elements) == 0 || len(elements)%2 != 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid regex_uri",
+			fmt.Sprintf("Attribute %s %s, got %d elements.", req.Path, v.Description(ctx), len(elements)),
+		)
+		return
+	}
+	for i := 0; i < len(elements); i += 2 {
+		pattern, ok := elements[i].(types.String)
+		if !ok || pattern.IsNull() || pattern.IsUnknown() {
+			continue
+		}
+		if err := checkRegex(pattern.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtListIndex(i),
+				"Invalid regex_uri",
+				fmt.Sprintf("Attribute %s must be a well formed regex: %s", req.Path.AtListIndex(i), err),
+			)
+		}
+	}
+}
+
+var _ validator.Object = redirectTargetValidator{}
+
+// redirectTargetValidator validates that a redirect has exactly one of uri, http_to_https and regex_uri.
+type redirectTargetValidator struct{}
+
+func (v redirectTargetValidator) Description(ctx context.Context) string {
+	return "exactly one of uri, http_to_https = true and regex_uri must be set"
+}
+
+func (v redirectTargetValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v redirectTargetValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	targets := 0
+	for name, value := range req.ConfigValue.Attributes() {
+		if name != "uri" && name != "http_to_https" && name != "regex_uri" {
+			continue
+		}
+		if value.IsUnknown() {
+			return
+		}
+		if httpToHttps, ok := value.(types.Bool); ok {
+			if httpToHttps.ValueBool() {
+				targets++
+			}
+			continue
+		}
+		if !value.IsNull() {
+			targets++
+		}
+	}
+	if targets != 1 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid redirect",
+			fmt.Sprintf("Attribute %s requires %s, got %d of them.", req.Path, v.Description(ctx), targets),
+		)
+	}
+}
+
+var _ validator.Object = corsCredentialValidator{}
+
+// corsCredentialValidator validates that a cors with allow_credential allows no * in the allow_ and expose_headers
+// attributes, browsers reject credentialed responses with a wildcard. Unset allow_ attributes default to *.
+type corsCredentialValidator struct{}
+
+func (v corsCredentialValidator) Description(ctx context.Context) string {
+	return "allow_origins, allow_methods, allow_headers and expose_headers must not contain * when allow_credential is true"
+}
+
+func (v corsCredentialValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v corsCredentialValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	attributes := req.ConfigValue.Attributes()
+	allowCredential, ok := attributes["allow_credential"].(types.Bool)
+	if !ok || !allowCredential.ValueBool() {
+		return
+	}
+	for _, name := range []string{"allow_origins", "allow_methods", "allow_headers", "expose_headers"} {
+		value, ok := attributes[name].(types.String)
+		if !ok || value.IsUnknown() {
+			continue
+		}
+		if value.IsNull() {
+			if name != "expose_headers" {
+				resp.Diagnostics.AddAttributeError(
+					req.Path.AtName(name),
+					"Invalid cors",
+					fmt.Sprintf("Attribute %s defaults to *, set it explicitly when allow_credential is true.", req.Path.AtName(name)),
+				)
+			}
+			continue
+		}
+		for _, item := range strings.Split(value.ValueString(), ",") {
+			if strings.TrimSpace(item) == "*" {
+				resp.Diagnostics.AddAttributeError(
+					req.Path.AtName(name),
+					"Invalid cors",
+					fmt.Sprintf("Attribute %s must not contain * when allow_credential is true, got %q.", req.Path.AtName(name), value.ValueString()),
+				)
+				break
+			}
+		}
+	}
+}
+
+var _ validator.String = jsonObjectValidator{}
+
+// jsonObjectValidator validates that a JSON encoded string is an object, so jsonencode([]) fails at plan time.
+type jsonObjectValidator struct{}
+
+func (v jsonObjectValidator) Description(ctx context.Context) string {
+	return "value must be a JSON object"
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonObjectValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var object map[string]any
+	err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &object)
+	if err == nil && object == nil {
+		err = fmt.Errorf("got null")
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSON object",
+			fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err),
+		)
+	}
+}